@@ -7,7 +7,6 @@ import (
         "crypto/sha256"
         "encoding/base64"
         "encoding/hex"
-        "math/big"
 
         "github.com/google/uuid"
 )
@@ -24,22 +23,23 @@ func SHA256Hash(input string) string {
         return hex.EncodeToString(hash[:])
 }
 
-// GenerateUUID generates a new UUID
+// GenerateUUID generates a new random (version 4) UUID
 func GenerateUUID() string {
         return uuid.New().String()
 }
 
-// RandomString generates a random string of specified length
+// RandomString generates a random alphanumeric string of specified length.
+// Like GenerateUUID it panics if the system random source fails; use a
+// Generator to handle the error or choose another alphabet.
 func RandomString(length int) string {
-        const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-        result := make([]byte, length)
-        
-        for i := range result {
-                num, _ := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
-                result[i] = charset[num.Int64()]
+        if length <= 0 {
+                return ""
         }
-        
-        return string(result)
+        result, err := Generator{Alphabet: AlphabetAlphanumeric, Length: length}.Generate()
+        if err != nil {
+                panic(err)
+        }
+        return result
 }
 
 // Base64Encode encodes a string to base64
@@ -74,13 +74,3 @@ func GenerateToken(length int) (string, error) {
         }
         return hex.EncodeToString(bytes), nil
 }
-
-// HashPassword creates a simple hash of a password (Note: Use bcrypt in production)
-func HashPassword(password string) string {
-        return SHA256Hash(password + "salt") // Simple example, use proper salt in production
-}
-
-// VerifyPassword verifies a password against its hash
-func VerifyPassword(password, hash string) bool {
-        return HashPassword(password) == hash
-}
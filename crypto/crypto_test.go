@@ -152,7 +152,10 @@ func TestHashAndVerifyPassword(t *testing.T) {
         }
 
         for _, password := range passwords {
-                hash := HashPassword(password)
+                hash, err := HashPasswordWith(password, testPasswordPolicy)
+                if err != nil {
+                        t.Fatalf("HashPassword(%q) returned error: %v", password, err)
+                }
                 
                 if !VerifyPassword(password, hash) {
                         t.Errorf("VerifyPassword failed for password %q", password)
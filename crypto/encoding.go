@@ -0,0 +1,693 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/ascii85"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Encoding is a binary-to-text encoding with one-shot and streaming forms
+type Encoding interface {
+	EncodeToString(src []byte) string
+	DecodeString(s string) ([]byte, error)
+	// NewEncoder returns a writer that encodes to w. Close must be called
+	// to flush the final partial block.
+	NewEncoder(w io.Writer) io.WriteCloser
+	// NewDecoder returns a reader that decodes the text read from r
+	NewDecoder(r io.Reader) io.Reader
+}
+
+// Encodings without parameters
+var (
+	// Base64Std is standard padded base64, as used by Base64Encode
+	Base64Std Encoding = base64Encoding{base64.StdEncoding}
+	// Base64RawStd is standard base64 without padding
+	Base64RawStd Encoding = base64Encoding{base64.RawStdEncoding}
+	// Base64URL is padded URL and filename safe base64
+	Base64URL Encoding = base64Encoding{base64.URLEncoding}
+	// Base64RawURL is URL and filename safe base64 without padding
+	Base64RawURL Encoding = base64Encoding{base64.RawURLEncoding}
+	// Base32 is padded RFC 4648 base32
+	Base32 Encoding = base32Encoding{base32.StdEncoding}
+	// Base32Raw is RFC 4648 base32 without padding
+	Base32Raw Encoding = base32Encoding{base32.StdEncoding.WithPadding(base32.NoPadding)}
+	// Crockford32 is Crockford's base32. Decoding is case-insensitive,
+	// reads I and L as 1 and O as 0, and ignores hyphens.
+	Crockford32 Encoding = crockfordEncoding{}
+	// Base58 is base58 with the Bitcoin alphabet
+	Base58 Encoding = wholeEncoding{base58Encode, base58Decode}
+	// Ascii85 is the Ascii85 encoding of btoa and PostScript, without the
+	// <~ ~> delimiters
+	Ascii85 Encoding = ascii85Encoding{}
+)
+
+// Z85 is the ZeroMQ variant of base85 from ZeroMQ RFC 32. It only encodes
+// input that is a multiple of 4 bytes long, so it is not an Encoding and
+// Encode returns an error instead.
+var Z85 = Z85Encoding{}
+
+var (
+	// ErrInvalidEncoding is returned when text is not valid in its encoding
+	ErrInvalidEncoding = errors.New("crypto: invalid encoding")
+	// ErrInvalidChecksum is returned when encoded data fails its checksum
+	ErrInvalidChecksum = errors.New("crypto: invalid checksum")
+)
+
+// base64Encoding adapts a *base64.Encoding to Encoding
+type base64Encoding struct {
+	*base64.Encoding
+}
+
+func (e base64Encoding) NewEncoder(w io.Writer) io.WriteCloser {
+	return base64.NewEncoder(e.Encoding, w)
+}
+
+func (e base64Encoding) NewDecoder(r io.Reader) io.Reader {
+	return base64.NewDecoder(e.Encoding, r)
+}
+
+// base32Encoding adapts a *base32.Encoding to Encoding
+type base32Encoding struct {
+	*base32.Encoding
+}
+
+func (e base32Encoding) NewEncoder(w io.Writer) io.WriteCloser {
+	return base32.NewEncoder(e.Encoding, w)
+}
+
+func (e base32Encoding) NewDecoder(r io.Reader) io.Reader {
+	return base32.NewDecoder(e.Encoding, &blockReader{r, 8})
+}
+
+// blockReader returns reads that are a whole number of blocks long, except
+// at the end of the input. base32.NewDecoder without padding fails on
+// short reads.
+type blockReader struct {
+	r    io.Reader
+	size int
+}
+
+func (b *blockReader) Read(p []byte) (int, error) {
+	if n := len(p) / b.size * b.size; n > 0 {
+		p = p[:n]
+	}
+	n, err := io.ReadFull(b.r, p)
+	if err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+	return n, err
+}
+
+// crockfordBase32 is the bit layout of RFC 4648 base32 with Crockford's
+// alphabet and no padding
+var crockfordBase32 = base32.NewEncoding(crockfordAlphabet).WithPadding(base32.NoPadding)
+
+type crockfordEncoding struct{}
+
+func (crockfordEncoding) EncodeToString(src []byte) string {
+	return crockfordBase32.EncodeToString(src)
+}
+
+func (crockfordEncoding) DecodeString(s string) ([]byte, error) {
+	return crockfordBase32.DecodeString(strings.Map(crockfordNormalize, s))
+}
+
+func (crockfordEncoding) NewEncoder(w io.Writer) io.WriteCloser {
+	return base32.NewEncoder(crockfordBase32, w)
+}
+
+func (crockfordEncoding) NewDecoder(r io.Reader) io.Reader {
+	return base32.NewDecoder(crockfordBase32, &blockReader{&crockfordReader{r}, 8})
+}
+
+// crockfordNormalize maps a character to its canonical Crockford form, or
+// drops it if it is a hyphen
+func crockfordNormalize(r rune) rune {
+	switch r {
+	case '-':
+		return -1
+	case 'I', 'i', 'L', 'l':
+		return '1'
+	case 'O', 'o':
+		return '0'
+	}
+	if r >= 'a' && r <= 'z' {
+		return r - 'a' + 'A'
+	}
+	return r
+}
+
+// crockfordReader normalizes Crockford base32 text as it is read
+type crockfordReader struct {
+	r io.Reader
+}
+
+func (c *crockfordReader) Read(p []byte) (int, error) {
+	for {
+		n, err := c.r.Read(p)
+		kept := 0
+		for _, b := range p[:n] {
+			if r := crockfordNormalize(rune(b)); r >= 0 {
+				p[kept] = byte(r)
+				kept++
+			}
+		}
+		// A read of nothing but hyphens must not look like a zero-length read
+		if kept > 0 || err != nil {
+			return kept, err
+		}
+	}
+}
+
+// ascii85Encoding adapts encoding/ascii85 to Encoding
+type ascii85Encoding struct{}
+
+func (ascii85Encoding) EncodeToString(src []byte) string {
+	dst := make([]byte, ascii85.MaxEncodedLen(len(src)))
+	return string(dst[:ascii85.Encode(dst, src)])
+}
+
+func (ascii85Encoding) DecodeString(s string) ([]byte, error) {
+	// "z" expands to 4 bytes
+	dst := make([]byte, 4*len(s))
+	n, _, err := ascii85.Decode(dst, []byte(s), true)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	return dst[:n], nil
+}
+
+func (ascii85Encoding) NewEncoder(w io.Writer) io.WriteCloser {
+	return ascii85.NewEncoder(w)
+}
+
+func (ascii85Encoding) NewDecoder(r io.Reader) io.Reader {
+	return ascii85.NewDecoder(r)
+}
+
+// z85Alphabet is the Z85 alphabet from ZeroMQ RFC 32
+const z85Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ.-:+=^!/*?&<>()[]{}@%$#"
+
+// Z85Encoding is the type of Z85. It has the methods of Encoding except
+// EncodeToString, which Encode replaces.
+type Z85Encoding struct{}
+
+// Encode encodes src, which must be a multiple of 4 bytes long
+func (Z85Encoding) Encode(src []byte) (string, error) {
+	if len(src)%4 != 0 {
+		return "", fmt.Errorf("%w: Z85 input length %d is not a multiple of 4", ErrInvalidEncoding, len(src))
+	}
+	dst := make([]byte, len(src)/4*5)
+	z85EncodeBlocks(dst, src)
+	return string(dst), nil
+}
+
+// DecodeString decodes s, which must be a multiple of 5 characters long
+func (Z85Encoding) DecodeString(s string) ([]byte, error) {
+	if len(s)%5 != 0 {
+		return nil, fmt.Errorf("%w: Z85 length %d is not a multiple of 5", ErrInvalidEncoding, len(s))
+	}
+	dst := make([]byte, len(s)/5*4)
+	if err := z85DecodeBlocks(dst, []byte(s)); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+// z85EncodeBlocks encodes the whole 4-byte blocks of src into dst
+func z85EncodeBlocks(dst, src []byte) {
+	for i := 0; i+4 <= len(src); i += 4 {
+		v := uint32(src[i])<<24 | uint32(src[i+1])<<16 | uint32(src[i+2])<<8 | uint32(src[i+3])
+		out := dst[i/4*5:]
+		for j := 4; j >= 0; j-- {
+			out[j] = z85Alphabet[v%85]
+			v /= 85
+		}
+	}
+}
+
+// z85DecodeBlocks decodes the whole 5-character blocks of src into dst
+func z85DecodeBlocks(dst, src []byte) error {
+	for i := 0; i+5 <= len(src); i += 5 {
+		var v uint64
+		for j := 0; j < 5; j++ {
+			d := strings.IndexByte(z85Alphabet, src[i+j])
+			if d < 0 {
+				return fmt.Errorf("%w: invalid Z85 character %q", ErrInvalidEncoding, src[i+j])
+			}
+			v = v*85 + uint64(d)
+		}
+		if v > 0xffffffff {
+			return fmt.Errorf("%w: Z85 block out of range", ErrInvalidEncoding)
+		}
+		out := dst[i/5*4:]
+		out[0], out[1], out[2], out[3] = byte(v>>24), byte(v>>16), byte(v>>8), byte(v)
+	}
+	return nil
+}
+
+// NewEncoder returns a writer that encodes to w. Close returns an error if
+// the total input was not a multiple of 4 bytes long.
+func (Z85Encoding) NewEncoder(w io.Writer) io.WriteCloser {
+	return &z85Encoder{w: w}
+}
+
+// NewDecoder returns a reader that decodes the text read from r
+func (Z85Encoding) NewDecoder(r io.Reader) io.Reader {
+	return &z85Decoder{r: r}
+}
+
+// z85Encoder encodes whole blocks as they are written
+type z85Encoder struct {
+	w       io.Writer
+	pending []byte
+	err     error
+}
+
+func (e *z85Encoder) Write(p []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	e.pending = append(e.pending, p...)
+	whole := len(e.pending) / 4 * 4
+	out := make([]byte, whole/4*5)
+	z85EncodeBlocks(out, e.pending[:whole])
+	if _, e.err = e.w.Write(out); e.err != nil {
+		return 0, e.err
+	}
+	e.pending = append(e.pending[:0], e.pending[whole:]...)
+	return len(p), nil
+}
+
+func (e *z85Encoder) Close() error {
+	if e.err == nil && len(e.pending) != 0 {
+		e.err = fmt.Errorf("%w: Z85 input is not a multiple of 4 bytes", ErrInvalidEncoding)
+	}
+	return e.err
+}
+
+// z85Decoder decodes whole blocks as they are read
+type z85Decoder struct {
+	r       io.Reader
+	pending []byte
+	out     []byte
+	err     error
+}
+
+func (d *z85Decoder) Read(p []byte) (int, error) {
+	for len(d.out) == 0 && d.err == nil {
+		buf := make([]byte, 4096)
+		n, err := d.r.Read(buf)
+		d.pending = append(d.pending, buf[:n]...)
+		whole := len(d.pending) / 5 * 5
+		d.out = make([]byte, whole/5*4)
+		if derr := z85DecodeBlocks(d.out, d.pending[:whole]); derr != nil {
+			d.out, d.err = nil, derr
+			break
+		}
+		d.pending = append(d.pending[:0], d.pending[whole:]...)
+		if err == io.EOF && len(d.pending) != 0 {
+			err = fmt.Errorf("%w: Z85 length is not a multiple of 5", ErrInvalidEncoding)
+		}
+		d.err = err
+	}
+	if len(d.out) > 0 {
+		n := copy(p, d.out)
+		d.out = d.out[n:]
+		return n, nil
+	}
+	return 0, d.err
+}
+
+// wholeEncoding adapts an encoding that is not made of independent
+// blocks, such as base58, to Encoding. Its streaming forms have to buffer
+// the whole input.
+type wholeEncoding struct {
+	encode func([]byte) string
+	decode func(string) ([]byte, error)
+}
+
+func (e wholeEncoding) EncodeToString(src []byte) string {
+	return e.encode(src)
+}
+
+func (e wholeEncoding) DecodeString(s string) ([]byte, error) {
+	return e.decode(s)
+}
+
+// NewEncoder returns a writer that buffers everything written and encodes
+// it to w on Close
+func (e wholeEncoding) NewEncoder(w io.Writer) io.WriteCloser {
+	return &wholeEncoder{w: w, encode: e.encode}
+}
+
+// NewDecoder returns a reader that reads r to the end before decoding it.
+// Surrounding whitespace, such as a final newline, is ignored.
+func (e wholeEncoding) NewDecoder(r io.Reader) io.Reader {
+	return &wholeDecoder{r: r, decode: e.decode}
+}
+
+type wholeEncoder struct {
+	w      io.Writer
+	buf    bytes.Buffer
+	encode func([]byte) string
+}
+
+func (e *wholeEncoder) Write(p []byte) (int, error) {
+	return e.buf.Write(p)
+}
+
+func (e *wholeEncoder) Close() error {
+	_, err := io.WriteString(e.w, e.encode(e.buf.Bytes()))
+	e.buf.Reset()
+	return err
+}
+
+type wholeDecoder struct {
+	r      io.Reader
+	decode func(string) ([]byte, error)
+	out    *bytes.Reader
+	err    error
+}
+
+func (d *wholeDecoder) Read(p []byte) (int, error) {
+	if d.out == nil && d.err == nil {
+		var data []byte
+		if data, d.err = io.ReadAll(d.r); d.err == nil {
+			data, d.err = d.decode(strings.TrimSpace(string(data)))
+			d.out = bytes.NewReader(data)
+		}
+	}
+	if d.err != nil {
+		return 0, d.err
+	}
+	return d.out.Read(p)
+}
+
+// base58Alphabet is the Bitcoin base58 alphabet
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58Encode encodes src as a big-endian number, with a leading "1" for
+// every leading zero byte
+func base58Encode(src []byte) string {
+	zeros := 0
+	for zeros < len(src) && src[zeros] == 0 {
+		zeros++
+	}
+
+	// Base58 digits, least significant first
+	var digits []byte
+	for _, b := range src[zeros:] {
+		carry := int(b)
+		for i := range digits {
+			carry += int(digits[i]) << 8
+			digits[i] = byte(carry % 58)
+			carry /= 58
+		}
+		for carry > 0 {
+			digits = append(digits, byte(carry%58))
+			carry /= 58
+		}
+	}
+
+	out := make([]byte, zeros+len(digits))
+	for i := 0; i < zeros; i++ {
+		out[i] = base58Alphabet[0]
+	}
+	for i, d := range digits {
+		out[len(out)-1-i] = base58Alphabet[d]
+	}
+	return string(out)
+}
+
+// base58Decode reverses base58Encode
+func base58Decode(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+
+	// Bytes, least significant first
+	var decoded []byte
+	for i := zeros; i < len(s); i++ {
+		carry := strings.IndexByte(base58Alphabet, s[i])
+		if carry < 0 {
+			return nil, fmt.Errorf("%w: invalid base58 character %q at %d", ErrInvalidEncoding, s[i], i)
+		}
+		for j := range decoded {
+			carry += int(decoded[j]) * 58
+			decoded[j] = byte(carry)
+			carry >>= 8
+		}
+		for carry > 0 {
+			decoded = append(decoded, byte(carry))
+			carry >>= 8
+		}
+	}
+
+	out := make([]byte, zeros+len(decoded))
+	for i, b := range decoded {
+		out[len(out)-1-i] = b
+	}
+	return out, nil
+}
+
+// NewBase58Check returns the Base58Check encoding used for Bitcoin
+// addresses and keys: a version byte, the payload and the first 4 bytes of
+// the payload's double SHA-256, in base58. Decoding verifies the checksum
+// and that the version matches.
+func NewBase58Check(version byte) Encoding {
+	return wholeEncoding{
+		encode: func(src []byte) string {
+			return Base58CheckEncode(version, src)
+		},
+		decode: func(s string) ([]byte, error) {
+			v, payload, err := Base58CheckDecode(s)
+			if err != nil {
+				return nil, err
+			}
+			if v != version {
+				return nil, fmt.Errorf("%w: Base58Check version %d; expected %d", ErrInvalidEncoding, v, version)
+			}
+			return payload, nil
+		},
+	}
+}
+
+// Base58CheckEncode encodes payload with a version byte and checksum
+func Base58CheckEncode(version byte, payload []byte) string {
+	data := make([]byte, 0, 1+len(payload)+4)
+	data = append(data, version)
+	data = append(data, payload...)
+	return base58Encode(append(data, base58Checksum(data)...))
+}
+
+// Base58CheckDecode decodes s and verifies its checksum, returning the
+// version byte and payload
+func Base58CheckDecode(s string) (byte, []byte, error) {
+	data, err := base58Decode(s)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(data) < 5 {
+		return 0, nil, fmt.Errorf("%w: Base58Check data too short", ErrInvalidEncoding)
+	}
+	body, sum := data[:len(data)-4], data[len(data)-4:]
+	if subtle.ConstantTimeCompare(sum, base58Checksum(body)) != 1 {
+		return 0, nil, ErrInvalidChecksum
+	}
+	return body[0], body[1:], nil
+}
+
+// base58Checksum returns the first 4 bytes of the double SHA-256 of data
+func base58Checksum(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	return second[:4]
+}
+
+// bech32Charset is the bech32 alphabet from BIP 173
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// NewBech32 returns the bech32 encoding of BIP 173 with the given
+// human-readable part. Decoding verifies the checksum and that the
+// human-readable part matches. The 90 character limit that BIP 173 sets
+// for segwit addresses is not enforced, so longer data such as Lightning
+// invoices can be encoded.
+func NewBech32(hrp string) (Encoding, error) {
+	if err := checkBech32HRP(hrp); err != nil {
+		return nil, err
+	}
+	hrp = strings.ToLower(hrp)
+	return wholeEncoding{
+		encode: func(src []byte) string {
+			s, _ := Bech32Encode(hrp, src)
+			return s
+		},
+		decode: func(s string) ([]byte, error) {
+			h, data, err := Bech32Decode(s)
+			if err != nil {
+				return nil, err
+			}
+			if h != hrp {
+				return nil, fmt.Errorf("%w: bech32 prefix %q; expected %q", ErrInvalidEncoding, h, hrp)
+			}
+			return data, nil
+		},
+	}, nil
+}
+
+// Bech32Encode encodes data with the human-readable part hrp, such as
+// "bc" or "cosmos", in lower case
+func Bech32Encode(hrp string, data []byte) (string, error) {
+	if err := checkBech32HRP(hrp); err != nil {
+		return "", err
+	}
+	hrp = strings.ToLower(hrp)
+	values := convertBits(data, 8, 5)
+	values = append(values, bech32Checksum(hrp, values)...)
+
+	var b strings.Builder
+	b.Grow(len(hrp) + 1 + len(values))
+	b.WriteString(hrp)
+	b.WriteByte('1')
+	for _, v := range values {
+		b.WriteByte(bech32Charset[v])
+	}
+	return b.String(), nil
+}
+
+// Bech32Decode decodes a bech32 string and verifies its checksum,
+// returning the lower case human-readable part and the data. Strings in
+// mixed case are rejected.
+func Bech32Decode(s string) (string, []byte, error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, fmt.Errorf("%w: bech32 string in mixed case", ErrInvalidEncoding)
+	}
+	s = strings.ToLower(s)
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 1 || len(s)-sep-1 < 6 {
+		return "", nil, fmt.Errorf("%w: bech32 separator missing or data too short", ErrInvalidEncoding)
+	}
+	hrp := s[:sep]
+	if err := checkBech32HRP(hrp); err != nil {
+		return "", nil, err
+	}
+
+	values := make([]byte, len(s)-sep-1)
+	for i := range values {
+		v := strings.IndexByte(bech32Charset, s[sep+1+i])
+		if v < 0 {
+			return "", nil, fmt.Errorf("%w: invalid bech32 character %q", ErrInvalidEncoding, s[sep+1+i])
+		}
+		values[i] = byte(v)
+	}
+	if bech32Polymod(append(bech32ExpandHRP(hrp), values...)) != 1 {
+		return "", nil, ErrInvalidChecksum
+	}
+
+	data, err := convertBitsStrict(values[:len(values)-6], 5, 8)
+	if err != nil {
+		return "", nil, err
+	}
+	return hrp, data, nil
+}
+
+// checkBech32HRP checks a human-readable part is 1 to 83 printable ASCII
+// characters
+func checkBech32HRP(hrp string) error {
+	if len(hrp) < 1 || len(hrp) > 83 {
+		return fmt.Errorf("%w: bech32 prefix must be 1 to 83 characters", ErrInvalidEncoding)
+	}
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return fmt.Errorf("%w: invalid bech32 prefix character %q", ErrInvalidEncoding, hrp[i])
+		}
+	}
+	return nil
+}
+
+// bech32Polymod computes the BCH checksum of BIP 173
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i, g := range generator {
+			if (top>>i)&1 == 1 {
+				chk ^= g
+			}
+		}
+	}
+	return chk
+}
+
+// bech32ExpandHRP expands the human-readable part for the checksum
+func bech32ExpandHRP(hrp string) []byte {
+	out := make([]byte, 0, 2*len(hrp)+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// bech32Checksum returns the 6 checksum values for hrp and data
+func bech32Checksum(hrp string, values []byte) []byte {
+	input := append(bech32ExpandHRP(hrp), values...)
+	mod := bech32Polymod(append(input, 0, 0, 0, 0, 0, 0)) ^ 1
+	sum := make([]byte, 6)
+	for i := range sum {
+		sum[i] = byte(mod>>(5*(5-i))) & 31
+	}
+	return sum
+}
+
+// convertBits regroups data from groups of from bits to groups of to bits,
+// padding the last group with zeros
+func convertBits(data []byte, from, to uint) []byte {
+	var out []byte
+	acc, bits := uint(0), uint(0)
+	for _, b := range data {
+		acc = acc<<from | uint(b)
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits)&(1<<to-1))
+		}
+	}
+	if bits > 0 {
+		out = append(out, byte(acc<<(to-bits))&(1<<to-1))
+	}
+	return out
+}
+
+// convertBitsStrict regroups data like convertBits, but rejects leftover
+// bits unless they are fewer than from and all zero
+func convertBitsStrict(data []byte, from, to uint) ([]byte, error) {
+	var out []byte
+	acc, bits := uint(0), uint(0)
+	for _, b := range data {
+		acc = acc<<from | uint(b)
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits)&(1<<to-1))
+		}
+	}
+	if bits >= from || acc&(1<<bits-1) != 0 {
+		return nil, fmt.Errorf("%w: invalid bech32 padding", ErrInvalidEncoding)
+	}
+	return out, nil
+}
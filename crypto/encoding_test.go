@@ -0,0 +1,242 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestEncodingVectors(t *testing.T) {
+	genesis, _ := hex.DecodeString("62e907b15cbf27d5425399ebf6f0fb50ebb88f18")
+	bitcoin := NewBase58Check(0)
+
+	tests := []struct {
+		name     string
+		enc      Encoding
+		input    []byte
+		expected string
+	}{
+		{"base64 url", Base64URL, []byte{0xfb, 0xff}, "-_8="},
+		{"base64 raw url", Base64RawURL, []byte{0xfb, 0xff}, "-_8"},
+		{"base64 raw std", Base64RawStd, []byte{0xfb, 0xff}, "+/8"},
+		{"base32", Base32, []byte("foobar"), "MZXW6YTBOI======"},
+		{"base32 raw", Base32Raw, []byte("foobar"), "MZXW6YTBOI"},
+		{"crockford", Crockford32, []byte{0xff, 0x00}, "ZW00"},
+		{"base58", Base58, []byte("Hello World!"), "2NEpo7TZRRrLZSi2U"},
+		{"base58 leading zeros", Base58, []byte{0, 0, 0x28, 0x7f, 0xb4, 0xcd}, "11233QC4"},
+		{"base58check address", bitcoin, genesis, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"},
+		{"ascii85", Ascii85, []byte("Man is d"), "9jqo^BlbD-"},
+		{"ascii85 zeros", Ascii85, []byte{0, 0, 0, 0}, "z"},
+	}
+
+	for _, test := range tests {
+		encoded := test.enc.EncodeToString(test.input)
+		if encoded != test.expected {
+			t.Errorf("%s: EncodeToString = %q; expected %q", test.name, encoded, test.expected)
+		}
+		decoded, err := test.enc.DecodeString(test.expected)
+		if err != nil {
+			t.Errorf("%s: DecodeString returned error: %v", test.name, err)
+			continue
+		}
+		if !bytes.Equal(decoded, test.input) {
+			t.Errorf("%s: DecodeString = %x; expected %x", test.name, decoded, test.input)
+		}
+	}
+}
+
+func TestEncodingStreams(t *testing.T) {
+	bech32, _ := NewBech32("share")
+	encodings := map[string]Encoding{
+		"base64 url": Base64URL, "base64 raw url": Base64RawURL, "base32": Base32, "base32 raw": Base32Raw,
+		"crockford": Crockford32, "base58": Base58, "base58check": NewBase58Check(5),
+		"ascii85": Ascii85, "bech32": bech32,
+	}
+	data := bytes.Repeat([]byte{0x00, 0x01, 0xfe, 0xff, 'g', 'o', 'u', 't'}, 40)
+
+	for name, enc := range encodings {
+		var encoded bytes.Buffer
+		w := enc.NewEncoder(&encoded)
+		// Write in uneven pieces to cross block boundaries
+		for rest := data; len(rest) > 0; {
+			n := 7
+			if n > len(rest) {
+				n = len(rest)
+			}
+			if _, err := w.Write(rest[:n]); err != nil {
+				t.Fatalf("%s: encoder Write returned error: %v", name, err)
+			}
+			rest = rest[n:]
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("%s: encoder Close returned error: %v", name, err)
+		}
+		if encoded.String() != enc.EncodeToString(data) {
+			t.Errorf("%s: streaming encoder differs from EncodeToString", name)
+		}
+
+		decoded, err := io.ReadAll(enc.NewDecoder(iotest.OneByteReader(&encoded)))
+		if err != nil {
+			t.Fatalf("%s: decoder returned error: %v", name, err)
+		}
+		if !bytes.Equal(decoded, data) {
+			t.Errorf("%s: streaming round trip = %x", name, decoded)
+		}
+	}
+}
+
+func TestCrockford32Decoding(t *testing.T) {
+	data := []byte("share-code")
+	canonical := Crockford32.EncodeToString(data)
+
+	// Lower case, hyphens and look-alike letters are all accepted
+	sloppy := strings.ToLower(canonical[:4]) + "-" + canonical[4:]
+	sloppy = strings.NewReplacer("1", "l", "0", "O").Replace(sloppy)
+	decoded, err := Crockford32.DecodeString(sloppy)
+	if err != nil || !bytes.Equal(decoded, data) {
+		t.Errorf("Crockford32.DecodeString(%q) = %q, %v", sloppy, decoded, err)
+	}
+
+	streamed, err := io.ReadAll(Crockford32.NewDecoder(strings.NewReader(sloppy)))
+	if err != nil || !bytes.Equal(streamed, data) {
+		t.Errorf("Crockford32 decoder on %q = %q, %v", sloppy, streamed, err)
+	}
+
+	if _, err := Crockford32.DecodeString("UUUU"); err == nil {
+		t.Error("Crockford32 should reject U")
+	}
+}
+
+func TestBase58Check(t *testing.T) {
+	version, payload, err := Base58CheckDecode("1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa")
+	if err != nil || version != 0 || len(payload) != 20 {
+		t.Errorf("Base58CheckDecode = %d, %x, %v", version, payload, err)
+	}
+
+	if _, _, err := Base58CheckDecode("1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb"); !errors.Is(err, ErrInvalidChecksum) {
+		t.Errorf("Base58CheckDecode of a corrupted address: got %v; expected ErrInvalidChecksum", err)
+	}
+	if _, err := NewBase58Check(5).DecodeString("1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("DecodeString with the wrong version: got %v; expected ErrInvalidEncoding", err)
+	}
+	if _, err := Base58.DecodeString("0OIl"); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("Base58.DecodeString of invalid characters: got %v; expected ErrInvalidEncoding", err)
+	}
+}
+
+func TestBech32(t *testing.T) {
+	// Valid strings from BIP 173
+	for _, valid := range []string{
+		"A12UEL5L",
+		"a12uel5l",
+		"an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs",
+		"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+		"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w",
+	} {
+		hrp, _, err := Bech32Decode(valid)
+		if err != nil {
+			t.Errorf("Bech32Decode(%q) returned error: %v", valid, err)
+		} else if hrp != strings.ToLower(hrp) {
+			t.Errorf("Bech32Decode(%q) prefix %q is not lower case", valid, hrp)
+		}
+	}
+
+	for _, invalid := range []string{
+		"pzry9x0s0muk",      // no separator
+		"1pzry9x0s0muk",     // empty prefix
+		"x1b4n0q5v",         // invalid character
+		"li1dgmt3",          // checksum too short
+		"A1G7SGD8",          // checksum computed with an upper case prefix
+		"a12UEL5L",          // mixed case
+		"abcdef1qpzry9x8gf", // bad checksum
+	} {
+		if _, _, err := Bech32Decode(invalid); err == nil {
+			t.Errorf("Bech32Decode(%q) should fail", invalid)
+		}
+	}
+
+	data := []byte{0x00, 0x14, 0x75, 0x1e, 0x76, 0xe8}
+	encoded, err := Bech32Encode("Cosmos", data)
+	if err != nil || !strings.HasPrefix(encoded, "cosmos1") {
+		t.Fatalf("Bech32Encode = %q, %v", encoded, err)
+	}
+	hrp, decoded, err := Bech32Decode(strings.ToUpper(encoded))
+	if err != nil || hrp != "cosmos" || !bytes.Equal(decoded, data) {
+		t.Errorf("Bech32Decode(%q) = %q, %x, %v", encoded, hrp, decoded, err)
+	}
+
+	corrupted := encoded[:len(encoded)-1] + "q"
+	if encoded[len(encoded)-1] == 'q' {
+		corrupted = encoded[:len(encoded)-1] + "p"
+	}
+	if _, _, err := Bech32Decode(corrupted); !errors.Is(err, ErrInvalidChecksum) {
+		t.Errorf("Bech32Decode of a corrupted string: got %v; expected ErrInvalidChecksum", err)
+	}
+
+	other, _ := NewBech32("osmo")
+	if _, err := other.DecodeString(encoded); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("DecodeString with the wrong prefix: got %v; expected ErrInvalidEncoding", err)
+	}
+	if _, err := NewBech32(""); err == nil {
+		t.Error("NewBech32 should reject an empty prefix")
+	}
+}
+
+func TestZ85(t *testing.T) {
+	// ZeroMQ RFC 32 test vector
+	input := []byte{0x86, 0x4f, 0xd2, 0x6f, 0xb5, 0x59, 0xf7, 0x5b}
+	encoded, err := Z85.Encode(input)
+	if err != nil || encoded != "HelloWorld" {
+		t.Errorf("Z85.Encode(%x) = %q, %v; expected HelloWorld", input, encoded, err)
+	}
+	decoded, err := Z85.DecodeString("HelloWorld")
+	if err != nil || !bytes.Equal(decoded, input) {
+		t.Errorf("Z85.DecodeString(HelloWorld) = %x, %v; expected %x", decoded, err, input)
+	}
+
+	data := bytes.Repeat([]byte{0x00, 0x01, 0xfe, 0xff, 'g', 'o', 'u', 't'}, 40)
+	var streamed bytes.Buffer
+	w := Z85.NewEncoder(&streamed)
+	for rest := data; len(rest) > 0; {
+		n := 7
+		if n > len(rest) {
+			n = len(rest)
+		}
+		if _, err := w.Write(rest[:n]); err != nil {
+			t.Fatalf("Z85 encoder Write returned error: %v", err)
+		}
+		rest = rest[n:]
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Z85 encoder Close returned error: %v", err)
+	}
+	if expected, _ := Z85.Encode(data); streamed.String() != expected {
+		t.Errorf("Z85 streaming encoder differs from Encode")
+	}
+	roundTrip, err := io.ReadAll(Z85.NewDecoder(iotest.OneByteReader(&streamed)))
+	if err != nil || !bytes.Equal(roundTrip, data) {
+		t.Errorf("Z85 streaming round trip = %x, %v", roundTrip, err)
+	}
+}
+
+func TestZ85Errors(t *testing.T) {
+	if encoded, err := Z85.Encode([]byte{1, 2, 3}); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("Z85.Encode with a partial block = %q, %v; expected ErrInvalidEncoding", encoded, err)
+	}
+
+	w := Z85.NewEncoder(io.Discard)
+	w.Write([]byte{1, 2, 3})
+	if err := w.Close(); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("Z85 encoder Close with a partial block: got %v; expected ErrInvalidEncoding", err)
+	}
+
+	for _, bad := range []string{"HelloWorl", "Hello\"orld", "#####"} {
+		if _, err := Z85.DecodeString(bad); !errors.Is(err, ErrInvalidEncoding) {
+			t.Errorf("Z85.DecodeString(%q): got %v; expected ErrInvalidEncoding", bad, err)
+		}
+	}
+}
@@ -0,0 +1,423 @@
+package crypto
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// CipherAlgorithm identifies an authenticated encryption algorithm. The
+// value is recorded in every envelope.
+type CipherAlgorithm byte
+
+// Supported authenticated encryption algorithms. Both take 32-byte keys.
+const (
+	// AES256GCM is AES-256 in Galois/Counter Mode with 12-byte nonces
+	AES256GCM CipherAlgorithm = 1
+	// XChaCha20Poly1305 uses 24-byte nonces, which can be chosen at random
+	// for any number of messages
+	XChaCha20Poly1305 CipherAlgorithm = 2
+)
+
+// String returns the name of the algorithm
+func (a CipherAlgorithm) String() string {
+	switch a {
+	case AES256GCM:
+		return "AES-256-GCM"
+	case XChaCha20Poly1305:
+		return "XChaCha20-Poly1305"
+	}
+	return fmt.Sprintf("CipherAlgorithm(%d)", byte(a))
+}
+
+// EncryptionKeySize is the key size of every CipherAlgorithm in bytes
+const EncryptionKeySize = 32
+
+// Envelope layout: version, algorithm, flags, key ID length, key ID, then
+// the nonce and the sealed data. Streams have a subkey salt and a nonce
+// prefix after the header, then the sealed chunks. The header is
+// authenticated along with the caller's associated data, so it cannot be
+// altered without detection.
+const (
+	envelopeVersion = 1
+	envelopeStream  = 1 << 0
+
+	// streamChunkSize is the plaintext size of each chunk of a stream
+	streamChunkSize = 64 * 1024
+	// streamNonceSuffix is the chunk counter and last-chunk flag that
+	// complete the random nonce prefix of a stream
+	streamNonceSuffix = 5
+	// streamSaltSize is the size of the random salt written after the
+	// header of a stream, from which its subkey is derived
+	streamSaltSize = 32
+)
+
+// streamKeyInfo separates stream subkeys from other keys derived with HKDF
+var streamKeyInfo = []byte("goutils stream v1")
+
+var (
+	// ErrInvalidEnvelope is returned for data that is not a well-formed
+	// envelope or uses an unknown version or algorithm
+	ErrInvalidEnvelope = errors.New("crypto: invalid ciphertext envelope")
+	// ErrDecrypt is returned when the ciphertext, associated data or key do
+	// not match. It deliberately does not say which.
+	ErrDecrypt = errors.New("crypto: message authentication failed")
+)
+
+// EncryptOptions configures Encrypt and EncryptStream
+type EncryptOptions struct {
+	// Algorithm defaults to AES256GCM
+	Algorithm CipherAlgorithm
+	// KeyID names the key in the envelope so the right key can be found for
+	// decryption. It is stored in clear text and may be up to 255 bytes.
+	KeyID string
+}
+
+// EnvelopeHeader is the clear text header of an encrypted envelope
+type EnvelopeHeader struct {
+	Version   byte
+	Algorithm CipherAlgorithm
+	KeyID     string
+	// Stream is set for envelopes written by EncryptStream
+	Stream bool
+}
+
+// NewEncryptionKey returns a random key for Encrypt
+func NewEncryptionKey() ([]byte, error) {
+	return RandomBytes(EncryptionKeySize)
+}
+
+func newAEAD(alg CipherAlgorithm, key []byte) (cipher.AEAD, error) {
+	if len(key) != EncryptionKeySize {
+		return nil, fmt.Errorf("crypto: %s needs a %d-byte key, got %d bytes", alg, EncryptionKeySize, len(key))
+	}
+	switch alg {
+	case AES256GCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+	case XChaCha20Poly1305:
+		return chacha20poly1305.NewX(key)
+	}
+	return nil, fmt.Errorf("crypto: unsupported cipher algorithm %d", byte(alg))
+}
+
+// encode returns the binary form of the header
+func (h EnvelopeHeader) encode() []byte {
+	flags := byte(0)
+	if h.Stream {
+		flags |= envelopeStream
+	}
+	out := []byte{h.Version, byte(h.Algorithm), flags, byte(len(h.KeyID))}
+	return append(out, h.KeyID...)
+}
+
+// ParseEnvelopeHeader reads the header of an envelope, typically to find
+// the key ID before decrypting
+func ParseEnvelopeHeader(envelope []byte) (EnvelopeHeader, error) {
+	h, _, err := parseEnvelopeHeader(envelope)
+	return h, err
+}
+
+func parseEnvelopeHeader(data []byte) (EnvelopeHeader, int, error) {
+	if len(data) < 4 || data[0] != envelopeVersion || data[2]&^envelopeStream != 0 {
+		return EnvelopeHeader{}, 0, ErrInvalidEnvelope
+	}
+	n := 4 + int(data[3])
+	if len(data) < n {
+		return EnvelopeHeader{}, 0, ErrInvalidEnvelope
+	}
+	h := EnvelopeHeader{
+		Version:   data[0],
+		Algorithm: CipherAlgorithm(data[1]),
+		KeyID:     string(data[4:n]),
+		Stream:    data[2]&envelopeStream != 0,
+	}
+	if h.Algorithm != AES256GCM && h.Algorithm != XChaCha20Poly1305 {
+		return EnvelopeHeader{}, 0, ErrInvalidEnvelope
+	}
+	return h, n, nil
+}
+
+func newHeader(opts EncryptOptions, stream bool) (EnvelopeHeader, error) {
+	if opts.Algorithm == 0 {
+		opts.Algorithm = AES256GCM
+	}
+	if len(opts.KeyID) > 255 {
+		return EnvelopeHeader{}, fmt.Errorf("crypto: key ID is %d bytes, at most 255 allowed", len(opts.KeyID))
+	}
+	return EnvelopeHeader{Version: envelopeVersion, Algorithm: opts.Algorithm, KeyID: opts.KeyID, Stream: stream}, nil
+}
+
+// additionalData binds the envelope header to the caller's associated data
+func additionalData(header, associatedData []byte) []byte {
+	ad := make([]byte, 0, len(header)+len(associatedData))
+	return append(append(ad, header...), associatedData...)
+}
+
+// Encrypt seals plaintext with a random nonce and returns a versioned
+// envelope recording the algorithm and key ID. associatedData is
+// authenticated but not encrypted; the same value must be given to Decrypt.
+func Encrypt(key, plaintext, associatedData []byte, opts EncryptOptions) ([]byte, error) {
+	h, err := newHeader(opts, false)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(h.Algorithm, key)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := RandomBytes(aead.NonceSize())
+	if err != nil {
+		return nil, err
+	}
+
+	header := h.encode()
+	out := make([]byte, 0, len(header)+len(nonce)+len(plaintext)+aead.Overhead())
+	out = append(append(out, header...), nonce...)
+	return aead.Seal(out, nonce, plaintext, additionalData(header, associatedData)), nil
+}
+
+// Decrypt opens an envelope produced by Encrypt. It returns ErrDecrypt if
+// the envelope was tampered with or the key or associated data differ.
+func Decrypt(key, envelope, associatedData []byte) ([]byte, error) {
+	h, n, err := parseEnvelopeHeader(envelope)
+	if err != nil {
+		return nil, err
+	}
+	if h.Stream {
+		return nil, fmt.Errorf("crypto: envelope is a stream, use DecryptStream")
+	}
+	aead, err := newAEAD(h.Algorithm, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(envelope) < n+aead.NonceSize()+aead.Overhead() {
+		return nil, ErrInvalidEnvelope
+	}
+
+	nonce := envelope[n : n+aead.NonceSize()]
+	plaintext, err := aead.Open(nil, nonce, envelope[n+aead.NonceSize():], additionalData(envelope[:n], associatedData))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+// streamNonce builds the nonce of chunk i from the stream's random prefix.
+// Marking the last chunk means a truncated stream fails to decrypt.
+func streamNonce(nonce, prefix []byte, i uint32, last bool) {
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[len(prefix):], i)
+	nonce[len(nonce)-1] = 0
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+}
+
+// streamAEAD returns the AEAD of a stream, keyed with a subkey derived from
+// key and the stream's salt. A fresh key per stream means the short random
+// nonce prefix only has to be unique within one stream.
+func streamAEAD(alg CipherAlgorithm, key, salt []byte) (cipher.AEAD, error) {
+	if len(key) != EncryptionKeySize {
+		return nil, fmt.Errorf("crypto: %s needs a %d-byte key, got %d bytes", alg, EncryptionKeySize, len(key))
+	}
+	subkey, err := HKDF(key, salt, streamKeyInfo, EncryptionKeySize)
+	if err != nil {
+		return nil, err
+	}
+	return newAEAD(alg, subkey)
+}
+
+// EncryptStream encrypts src to dst in chunks of 64 KiB, so that files of
+// any size can be encrypted in constant memory. Each chunk is authenticated
+// separately and their order and the end of the stream are protected. Every
+// stream is encrypted with its own subkey, derived from key and a random
+// salt stored after the header. It returns the number of bytes written.
+func EncryptStream(dst io.Writer, src io.Reader, key, associatedData []byte, opts EncryptOptions) (int64, error) {
+	h, err := newHeader(opts, true)
+	if err != nil {
+		return 0, err
+	}
+	salt, err := RandomBytes(streamSaltSize)
+	if err != nil {
+		return 0, err
+	}
+	aead, err := streamAEAD(h.Algorithm, key, salt)
+	if err != nil {
+		return 0, err
+	}
+	prefix, err := RandomBytes(aead.NonceSize() - streamNonceSuffix)
+	if err != nil {
+		return 0, err
+	}
+
+	header := h.encode()
+	ad := additionalData(header, associatedData)
+	bw := bufio.NewWriter(dst)
+	written := int64(0)
+	write := func(p []byte) {
+		n, _ := bw.Write(p)
+		written += int64(n)
+	}
+	write(header)
+	write(salt)
+	write(prefix)
+
+	br := bufio.NewReaderSize(src, streamChunkSize)
+	nonce := make([]byte, aead.NonceSize())
+	chunk := make([]byte, streamChunkSize)
+	sealed := make([]byte, 0, streamChunkSize+aead.Overhead())
+	for i := uint32(0); ; i++ {
+		n, err := io.ReadFull(br, chunk)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return written, err
+		}
+		last := err != nil
+		if !last {
+			if _, err := br.Peek(1); err == io.EOF {
+				last = true
+			} else if err != nil {
+				return written, err
+			}
+		}
+		if i == ^uint32(0) && !last {
+			return written, fmt.Errorf("crypto: stream too long")
+		}
+
+		streamNonce(nonce, prefix, i, last)
+		write(aead.Seal(sealed[:0], nonce, chunk[:n], ad))
+		if last {
+			break
+		}
+	}
+
+	// bufio.Writer keeps the first write error and reports it on Flush
+	return written, bw.Flush()
+}
+
+// DecryptStream decrypts a stream written by EncryptStream from src to dst
+// and returns the number of plaintext bytes written. Chunks are verified
+// before they are written, but a stream that fails part way leaves the
+// chunks before the failure in dst.
+func DecryptStream(dst io.Writer, src io.Reader, key, associatedData []byte) (int64, error) {
+	br := bufio.NewReaderSize(src, streamChunkSize)
+	fixed, err := br.Peek(4)
+	if err != nil {
+		return 0, ErrInvalidEnvelope
+	}
+	raw, err := br.Peek(4 + int(fixed[3]))
+	if err != nil {
+		return 0, ErrInvalidEnvelope
+	}
+	h, n, err := parseEnvelopeHeader(raw)
+	if err != nil {
+		return 0, err
+	}
+	if !h.Stream {
+		return 0, fmt.Errorf("crypto: envelope is not a stream, use Decrypt")
+	}
+	header := append([]byte(nil), raw[:n]...)
+	br.Discard(n)
+
+	salt := make([]byte, streamSaltSize)
+	if _, err := io.ReadFull(br, salt); err != nil {
+		return 0, ErrInvalidEnvelope
+	}
+	aead, err := streamAEAD(h.Algorithm, key, salt)
+	if err != nil {
+		return 0, err
+	}
+	prefix := make([]byte, aead.NonceSize()-streamNonceSuffix)
+	if _, err := io.ReadFull(br, prefix); err != nil {
+		return 0, ErrInvalidEnvelope
+	}
+
+	ad := additionalData(header, associatedData)
+	nonce := make([]byte, aead.NonceSize())
+	chunk := make([]byte, streamChunkSize+aead.Overhead())
+	plain := make([]byte, 0, streamChunkSize)
+	written := int64(0)
+	for i := uint32(0); ; i++ {
+		n, err := io.ReadFull(br, chunk)
+		if err != nil && err != io.ErrUnexpectedEOF {
+			if err == io.EOF {
+				// Even an empty last chunk carries a tag, so the stream was cut short
+				return written, ErrDecrypt
+			}
+			return written, err
+		}
+		last := err != nil
+		if !last {
+			if _, err := br.Peek(1); err == io.EOF {
+				last = true
+			} else if err != nil {
+				return written, err
+			}
+		}
+
+		streamNonce(nonce, prefix, i, last)
+		plain, err = aead.Open(plain[:0], nonce, chunk[:n], ad)
+		if err != nil {
+			return written, ErrDecrypt
+		}
+		m, err := dst.Write(plain)
+		written += int64(m)
+		if err != nil {
+			return written, err
+		}
+		if last {
+			return written, nil
+		}
+	}
+}
+
+// EncryptFile encrypts the file at src into a new file at dst with
+// EncryptStream. dst is removed again if encryption fails.
+func EncryptFile(src, dst string, key, associatedData []byte, opts EncryptOptions) error {
+	return transformFile(src, dst, func(w io.Writer, r io.Reader) error {
+		_, err := EncryptStream(w, r, key, associatedData, opts)
+		return err
+	})
+}
+
+// DecryptFile decrypts a file written by EncryptFile. dst is removed again
+// if decryption fails, so no unauthenticated plaintext is left behind.
+func DecryptFile(src, dst string, key, associatedData []byte) error {
+	return transformFile(src, dst, func(w io.Writer, r io.Reader) error {
+		_, err := DecryptStream(w, r, key, associatedData)
+		return err
+	})
+}
+
+func transformFile(src, dst string, transform func(io.Writer, io.Reader) error) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := transform(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return nil
+}
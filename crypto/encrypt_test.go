@@ -0,0 +1,143 @@
+package crypto
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	key, err := NewEncryptionKey()
+	if err != nil {
+		t.Fatalf("NewEncryptionKey returned error: %v", err)
+	}
+	plaintext := []byte("account=42;token=s3cr3t")
+	ad := []byte("users.api_token")
+
+	for _, alg := range []CipherAlgorithm{AES256GCM, XChaCha20Poly1305} {
+		envelope, err := Encrypt(key, plaintext, ad, EncryptOptions{Algorithm: alg, KeyID: "2024-01"})
+		if err != nil {
+			t.Fatalf("Encrypt(%s) returned error: %v", alg, err)
+		}
+
+		header, err := ParseEnvelopeHeader(envelope)
+		if err != nil || header.Algorithm != alg || header.KeyID != "2024-01" || header.Stream {
+			t.Errorf("ParseEnvelopeHeader = %+v, %v", header, err)
+		}
+
+		decrypted, err := Decrypt(key, envelope, ad)
+		if err != nil || !bytes.Equal(decrypted, plaintext) {
+			t.Errorf("Decrypt(%s) = %q, %v; expected %q", alg, decrypted, err, plaintext)
+		}
+
+		if _, err := Decrypt(key, envelope, []byte("users.password")); err != ErrDecrypt {
+			t.Errorf("Decrypt(%s) with other associated data: got %v; expected ErrDecrypt", alg, err)
+		}
+
+		// Changing the key ID in the header must be detected
+		tampered := append([]byte(nil), envelope...)
+		tampered[5] ^= 1
+		if _, err := Decrypt(key, tampered, ad); err != ErrDecrypt {
+			t.Errorf("Decrypt(%s) of a tampered header: got %v; expected ErrDecrypt", alg, err)
+		}
+
+		tampered = append([]byte(nil), envelope...)
+		tampered[len(tampered)-1] ^= 1
+		if _, err := Decrypt(key, tampered, ad); err != ErrDecrypt {
+			t.Errorf("Decrypt(%s) of a tampered ciphertext: got %v; expected ErrDecrypt", alg, err)
+		}
+	}
+}
+
+func TestEncryptUsesRandomNonces(t *testing.T) {
+	key, _ := NewEncryptionKey()
+	a, _ := Encrypt(key, []byte("same"), nil, EncryptOptions{})
+	b, _ := Encrypt(key, []byte("same"), nil, EncryptOptions{})
+	if bytes.Equal(a, b) {
+		t.Error("encrypting the same plaintext twice should give different envelopes")
+	}
+}
+
+func TestEncryptErrors(t *testing.T) {
+	key, _ := NewEncryptionKey()
+	if _, err := Encrypt(key[:16], []byte("x"), nil, EncryptOptions{}); err == nil {
+		t.Error("Encrypt should reject a short key")
+	}
+	if _, err := Encrypt(key, []byte("x"), nil, EncryptOptions{Algorithm: 9}); err == nil {
+		t.Error("Encrypt should reject an unknown algorithm")
+	}
+	for _, envelope := range [][]byte{nil, {1, 1, 0}, {2, 1, 0, 0}, {1, 1, 0, 10, 'a'}, {1, 1, 0, 0, 1, 2, 3}} {
+		if _, err := Decrypt(key, envelope, nil); err != ErrInvalidEnvelope {
+			t.Errorf("Decrypt(%v) = %v; expected ErrInvalidEnvelope", envelope, err)
+		}
+	}
+}
+
+func TestEncryptStream(t *testing.T) {
+	key, _ := NewEncryptionKey()
+	sizes := []int{0, 1, streamChunkSize - 1, streamChunkSize, streamChunkSize + 1, 3*streamChunkSize + 17}
+
+	for _, alg := range []CipherAlgorithm{AES256GCM, XChaCha20Poly1305} {
+		for _, size := range sizes {
+			plaintext := bytes.Repeat([]byte("0123456789"), size/10+1)[:size]
+			var sealed bytes.Buffer
+			if _, err := EncryptStream(&sealed, bytes.NewReader(plaintext), key, nil, EncryptOptions{Algorithm: alg}); err != nil {
+				t.Fatalf("EncryptStream(%s, %d bytes) returned error: %v", alg, size, err)
+			}
+			envelope := sealed.Bytes()
+
+			var opened bytes.Buffer
+			n, err := DecryptStream(&opened, bytes.NewReader(envelope), key, nil)
+			if err != nil || n != int64(size) || !bytes.Equal(opened.Bytes(), plaintext) {
+				t.Errorf("DecryptStream(%s, %d bytes) = %d, %v", alg, size, n, err)
+			}
+
+			// The salt selects the stream's subkey, so altering it fails
+			salted := append([]byte(nil), envelope...)
+			salted[4] ^= 0x01
+			if _, err := DecryptStream(&bytes.Buffer{}, bytes.NewReader(salted), key, nil); err != ErrDecrypt {
+				t.Errorf("DecryptStream(%s) with an altered salt: got %v; expected ErrDecrypt", alg, err)
+			}
+
+			// Cutting the stream at a chunk boundary must be detected
+			if size > streamChunkSize {
+				cut := len(envelope) - (size % streamChunkSize) - 16
+				if _, err := DecryptStream(&bytes.Buffer{}, bytes.NewReader(envelope[:cut]), key, nil); err != ErrDecrypt {
+					t.Errorf("DecryptStream(%s) of a truncated stream: got %v; expected ErrDecrypt", alg, err)
+				}
+			}
+		}
+	}
+}
+
+func TestEncryptFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "plain.txt")
+	enc := filepath.Join(dir, "plain.txt.enc")
+	dec := filepath.Join(dir, "plain.out")
+	content := bytes.Repeat([]byte("backup data\n"), 20000)
+	if err := os.WriteFile(src, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	key, _ := NewEncryptionKey()
+	if err := EncryptFile(src, enc, key, nil, EncryptOptions{KeyID: "backup"}); err != nil {
+		t.Fatalf("EncryptFile returned error: %v", err)
+	}
+	if err := DecryptFile(enc, dec, key, nil); err != nil {
+		t.Fatalf("DecryptFile returned error: %v", err)
+	}
+	result, _ := os.ReadFile(dec)
+	if !bytes.Equal(result, content) {
+		t.Error("DecryptFile did not restore the original content")
+	}
+
+	other, _ := NewEncryptionKey()
+	if err := DecryptFile(enc, dec, other, nil); err != ErrDecrypt {
+		t.Errorf("DecryptFile with the wrong key: got %v; expected ErrDecrypt", err)
+	}
+	if _, err := os.Stat(dec); !os.IsNotExist(err) {
+		t.Error("DecryptFile should remove its output when decryption fails")
+	}
+}
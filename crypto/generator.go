@@ -0,0 +1,296 @@
+package crypto
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Alphabets for Generator
+const (
+	AlphabetLower        = "abcdefghijklmnopqrstuvwxyz"
+	AlphabetUpper        = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	AlphabetDigits       = "0123456789"
+	AlphabetAlphanumeric = AlphabetUpper + AlphabetLower + AlphabetDigits
+	// AlphabetSymbols only holds characters counted as special by
+	// validation.IsStrongPassword
+	AlphabetSymbols = "!@#$%^&*()-_=+[]{};:,.<>?/"
+	// AlphabetURLSafe is the 64-character alphabet used by NanoID
+	AlphabetURLSafe   = "useandom-26T198340PX75pxJACKVERYMINDBUSHWOLF_GQZbfghjklqvwyzrict"
+	AlphabetCrockford = crockfordAlphabet
+	// AlphabetNoLookAlike leaves out characters that are easily confused
+	// when copied by hand: 0/O/o, 1/I/l, 2/Z/z, 5/S/s and u/v
+	AlphabetNoLookAlike = "346789ABCDEFGHJKLMNPQRTUVWXYabcdefghijkmnpqrtwxy"
+)
+
+// NanoIDLength is the default NanoID length, with about as many random bits
+// as a version 4 UUID
+const NanoIDLength = 21
+
+// MinPasswordLength is the shortest length GeneratePassword accepts, the
+// minimum of validation.IsStrongPassword
+const MinPasswordLength = 8
+
+// ErrInvalidGenerator is returned when a Generator cannot produce output
+var ErrInvalidGenerator = errors.New("crypto: invalid generator")
+
+// Rejection sampling bounds for Generate. A Generator is rejected when a
+// random string meets its Required classes with a lower probability than
+// minRequiredOdds, and above it all of maxGenerateAttempts draws fail with
+// a probability below e^-65.
+const (
+	minRequiredOdds     = 1e-3
+	maxGenerateAttempts = 1 << 16
+)
+
+// Generator produces random strings from an alphabet. Every character is
+// chosen uniformly, and strings missing one of the Required classes are
+// discarded and drawn again, so the output is uniform over all strings
+// that satisfy the requirements. Requirements that fewer than one random
+// string in a thousand meet, such as many single-character classes in a
+// short string, are rejected with ErrInvalidGenerator.
+type Generator struct {
+	// Alphabet holds the characters to choose from. If empty, the union
+	// of the Required classes is used.
+	Alphabet string
+	Length   int
+	// Required lists character classes, each a subset of Alphabet, that
+	// must all appear at least once
+	Required []string
+}
+
+// DefaultPasswordGenerator generates 20-character passwords with upper and
+// lower case letters, digits and symbols, satisfying
+// validation.IsStrongPassword
+var DefaultPasswordGenerator = Generator{
+	Length:   20,
+	Required: []string{AlphabetUpper, AlphabetLower, AlphabetDigits, AlphabetSymbols},
+}
+
+// Generate returns a new random string
+func (g Generator) Generate() (string, error) {
+	alphabet, classes, err := g.compile()
+	if err != nil {
+		return "", err
+	}
+
+	if len(classes) > 0 {
+		odds := log2Int(countMatching(alphabet, classes, g.Length)) - float64(g.Length)*math.Log2(float64(len(alphabet)))
+		if odds < math.Log2(minRequiredOdds) {
+			return "", fmt.Errorf("%w: random strings of length %d too rarely contain every required class", ErrInvalidGenerator, g.Length)
+		}
+	}
+
+	out := make([]rune, g.Length)
+	for attempt := 0; attempt < maxGenerateAttempts; attempt++ {
+		for i := range out {
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+			if err != nil {
+				return "", err
+			}
+			out[i] = alphabet[n.Int64()]
+		}
+		if containsClasses(out, classes) {
+			return string(out), nil
+		}
+	}
+	return "", fmt.Errorf("%w: no string contained every required class in %d attempts", ErrInvalidGenerator, maxGenerateAttempts)
+}
+
+// Entropy returns the number of bits of entropy in a string from g: the
+// base 2 logarithm of the number of distinct strings it can produce. It is
+// zero when g is invalid.
+func (g Generator) Entropy() float64 {
+	alphabet, classes, err := g.compile()
+	if err != nil {
+		return 0
+	}
+	if len(classes) == 0 {
+		return float64(g.Length) * math.Log2(float64(len(alphabet)))
+	}
+	return log2Int(countMatching(alphabet, classes, g.Length))
+}
+
+// countMatching returns the number of strings of the given length over
+// alphabet that contain every class, by inclusion-exclusion over the sets
+// of classes left out
+func countMatching(alphabet []rune, classes []map[rune]bool, length int) *big.Int {
+	count := new(big.Int)
+	for mask := 0; mask < 1<<len(classes); mask++ {
+		excluded := map[rune]bool{}
+		sign := 1
+		for i, class := range classes {
+			if mask&(1<<i) != 0 {
+				for r := range class {
+					excluded[r] = true
+				}
+				sign = -sign
+			}
+		}
+		term := new(big.Int).Exp(big.NewInt(int64(len(alphabet)-len(excluded))), big.NewInt(int64(length)), nil)
+		if sign < 0 {
+			term.Neg(term)
+		}
+		count.Add(count, term)
+	}
+	return count
+}
+
+// compile validates g and returns its alphabet and required classes as
+// rune sets
+func (g Generator) compile() ([]rune, []map[rune]bool, error) {
+	if g.Length <= 0 {
+		return nil, nil, fmt.Errorf("%w: length must be positive", ErrInvalidGenerator)
+	}
+	if len(g.Required) > g.Length {
+		return nil, nil, fmt.Errorf("%w: length %d is shorter than the %d required classes", ErrInvalidGenerator, g.Length, len(g.Required))
+	}
+
+	source := g.Alphabet
+	if source == "" {
+		source = strings.Join(g.Required, "")
+	}
+	if !utf8.ValidString(source) {
+		return nil, nil, fmt.Errorf("%w: alphabet is not valid UTF-8", ErrInvalidGenerator)
+	}
+	seen := map[rune]bool{}
+	var alphabet []rune
+	for _, r := range source {
+		if !seen[r] {
+			seen[r] = true
+			alphabet = append(alphabet, r)
+		}
+	}
+	if len(alphabet) < 2 {
+		return nil, nil, fmt.Errorf("%w: alphabet needs at least 2 distinct characters", ErrInvalidGenerator)
+	}
+
+	classes := make([]map[rune]bool, len(g.Required))
+	for i, required := range g.Required {
+		classes[i] = map[rune]bool{}
+		for _, r := range required {
+			if !seen[r] {
+				return nil, nil, fmt.Errorf("%w: required character %q is not in the alphabet", ErrInvalidGenerator, r)
+			}
+			classes[i][r] = true
+		}
+		if len(classes[i]) == 0 {
+			return nil, nil, fmt.Errorf("%w: required class %d is empty", ErrInvalidGenerator, i)
+		}
+	}
+	return alphabet, classes, nil
+}
+
+// containsClasses reports whether s has a character from every class
+func containsClasses(s []rune, classes []map[rune]bool) bool {
+	for _, class := range classes {
+		found := false
+		for _, r := range s {
+			if class[r] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// log2Int returns the base 2 logarithm of a positive integer of any size
+func log2Int(n *big.Int) float64 {
+	if n.Sign() <= 0 {
+		return 0
+	}
+	shift := 0
+	if bits := n.BitLen(); bits > 64 {
+		shift = bits - 64
+		n = new(big.Int).Rsh(n, uint(shift))
+	}
+	f, _ := new(big.Float).SetInt(n).Float64()
+	return math.Log2(f) + float64(shift)
+}
+
+// GeneratePassword returns a password of the given length from
+// DefaultPasswordGenerator's character classes. Lengths below
+// MinPasswordLength return ErrInvalidGenerator.
+func GeneratePassword(length int) (string, error) {
+	if length < MinPasswordLength {
+		return "", fmt.Errorf("%w: password length %d is shorter than %d", ErrInvalidGenerator, length, MinPasswordLength)
+	}
+	g := DefaultPasswordGenerator
+	g.Length = length
+	return g.Generate()
+}
+
+// NanoID returns a NanoID: 21 characters from the URL-safe alphabet
+func NanoID() (string, error) {
+	return NanoIDWith(AlphabetURLSafe, NanoIDLength)
+}
+
+// NanoIDWith returns a NanoID of the given size from a custom alphabet, as
+// customAlphabet does in the JavaScript library
+func NanoIDWith(alphabet string, size int) (string, error) {
+	return Generator{Alphabet: alphabet, Length: size}.Generate()
+}
+
+// PassphraseWordCount is the number of words in the passphrase wordlist
+const PassphraseWordCount = 7776
+
+// effWordlist is the EFF large wordlist for five-dice diceware, one
+// "<dice rolls>\t<word>" entry per line
+//
+//go:embed eff_large_wordlist.txt
+var effWordlist []byte
+
+var (
+	passphraseWords     []string
+	passphraseWordsOnce sync.Once
+)
+
+// wordlist returns the words of the embedded wordlist
+func wordlist() []string {
+	passphraseWordsOnce.Do(func() {
+		scanner := bufio.NewScanner(bytes.NewReader(effWordlist))
+		for scanner.Scan() {
+			if _, word, ok := strings.Cut(scanner.Text(), "\t"); ok {
+				passphraseWords = append(passphraseWords, word)
+			}
+		}
+	})
+	return passphraseWords
+}
+
+// GeneratePassphrase returns a diceware passphrase of the given number of
+// words from the EFF large wordlist, joined by separator. Each word adds
+// about 12.9 bits of entropy; six words are a good default.
+func GeneratePassphrase(words int, separator string) (string, error) {
+	if words <= 0 {
+		return "", fmt.Errorf("%w: word count must be positive", ErrInvalidGenerator)
+	}
+	list := wordlist()
+	chosen := make([]string, words)
+	for i := range chosen {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(list))))
+		if err != nil {
+			return "", err
+		}
+		chosen[i] = list[n.Int64()]
+	}
+	return strings.Join(chosen, separator), nil
+}
+
+// PassphraseEntropy returns the bits of entropy in a passphrase of the
+// given number of words from GeneratePassphrase
+func PassphraseEntropy(words int) float64 {
+	return float64(words) * math.Log2(PassphraseWordCount)
+}
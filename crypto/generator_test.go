@@ -0,0 +1,154 @@
+package crypto
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/yourusername/goutils/validation"
+)
+
+func TestGeneratorAlphabets(t *testing.T) {
+	alphabets := []string{AlphabetURLSafe, AlphabetCrockford, AlphabetNoLookAlike, "αβγδ"}
+
+	for _, alphabet := range alphabets {
+		g := Generator{Alphabet: alphabet, Length: 32}
+		result, err := g.Generate()
+		if err != nil {
+			t.Fatalf("Generate(%q) returned error: %v", alphabet, err)
+		}
+		if utf8.RuneCountInString(result) != 32 {
+			t.Errorf("Generate(%q) = %q; expected 32 characters", alphabet, result)
+		}
+		for _, r := range result {
+			if !strings.ContainsRune(alphabet, r) {
+				t.Errorf("Generate(%q) produced %q outside the alphabet", alphabet, r)
+			}
+		}
+	}
+
+	if strings.ContainsAny(AlphabetNoLookAlike, "0Oo1Il2Zz5Ssuv") {
+		t.Error("AlphabetNoLookAlike contains look-alike characters")
+	}
+}
+
+func TestGeneratePasswordIsStrong(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		// Short passwords are the most likely to miss a class
+		password, err := GeneratePassword(MinPasswordLength)
+		if err != nil {
+			t.Fatalf("GeneratePassword returned error: %v", err)
+		}
+		if !validation.IsStrongPassword(password) {
+			t.Fatalf("GeneratePassword produced %q, which IsStrongPassword rejects", password)
+		}
+	}
+
+	password, _ := DefaultPasswordGenerator.Generate()
+	if len(password) != 20 || !validation.IsStrongPassword(password) {
+		t.Errorf("DefaultPasswordGenerator produced %q", password)
+	}
+
+	for _, length := range []int{0, 4, MinPasswordLength - 1} {
+		if password, err := GeneratePassword(length); !errors.Is(err, ErrInvalidGenerator) {
+			t.Errorf("GeneratePassword(%d) = %q, %v; expected ErrInvalidGenerator", length, password, err)
+		}
+	}
+}
+
+func TestGeneratorEntropy(t *testing.T) {
+	tests := []struct {
+		name     string
+		g        Generator
+		expected float64
+	}{
+		{"nanoid", Generator{Alphabet: AlphabetURLSafe, Length: NanoIDLength}, 126},
+		{"hex", Generator{Alphabet: "0123456789abcdef", Length: 32}, 128},
+		// Only "ab" and "ba" contain both classes
+		{"required", Generator{Length: 2, Required: []string{"a", "b"}}, 1},
+		// All strings, less those without "a" or without "b", plus those
+		// with neither: 26^4 - 2*25^4 + 24^4
+		{"one each", Generator{Alphabet: AlphabetLower, Length: 4, Required: []string{"a", "b"}}, math.Log2(456976 - 2*390625 + 331776)},
+		{"invalid", Generator{Alphabet: "a", Length: 10}, 0},
+	}
+
+	for _, test := range tests {
+		if got := test.g.Entropy(); math.Abs(got-test.expected) > 1e-9 {
+			t.Errorf("%s: Entropy() = %v; expected %v", test.name, got, test.expected)
+		}
+	}
+
+	unconstrained := Generator{Alphabet: AlphabetAlphanumeric + AlphabetSymbols, Length: 12}
+	constrained := unconstrained
+	constrained.Required = DefaultPasswordGenerator.Required
+	if c, u := constrained.Entropy(), unconstrained.Entropy(); c >= u || c < u-2 {
+		t.Errorf("required classes changed entropy from %v to %v", u, c)
+	}
+}
+
+func TestGeneratorErrors(t *testing.T) {
+	invalid := []Generator{
+		{Alphabet: AlphabetLower, Length: 0},
+		{Alphabet: "aaaa", Length: 8},
+		{Alphabet: AlphabetLower, Length: 8, Required: []string{AlphabetDigits}},
+		{Length: 2, Required: []string{"a", "b", "c"}},
+		{Alphabet: "ab\xff", Length: 4},
+		// Eight single characters in eight places: about 1 in 10^11 tries
+		{Alphabet: AlphabetNoLookAlike, Length: 8, Required: []string{"A", "B", "C", "D", "E", "F", "G", "H"}},
+	}
+
+	for _, g := range invalid {
+		if _, err := g.Generate(); !errors.Is(err, ErrInvalidGenerator) {
+			t.Errorf("Generate(%+v): got %v; expected ErrInvalidGenerator", g, err)
+		}
+	}
+}
+
+func TestNanoID(t *testing.T) {
+	id, err := NanoID()
+	if err != nil {
+		t.Fatalf("NanoID returned error: %v", err)
+	}
+	if len(id) != 21 || strings.Trim(id, AlphabetURLSafe) != "" {
+		t.Errorf("NanoID = %q", id)
+	}
+
+	id, _ = NanoIDWith("1234567890abcdef", 10)
+	if len(id) != 10 || strings.Trim(id, "1234567890abcdef") != "" {
+		t.Errorf("NanoIDWith = %q", id)
+	}
+}
+
+func TestGeneratePassphrase(t *testing.T) {
+	words := wordlist()
+	if len(words) != PassphraseWordCount || words[0] != "abacus" || words[len(words)-1] != "zoom" {
+		t.Fatalf("embedded wordlist has %d words", len(words))
+	}
+	known := map[string]bool{}
+	for _, word := range words {
+		known[word] = true
+	}
+
+	passphrase, err := GeneratePassphrase(6, " ")
+	if err != nil {
+		t.Fatalf("GeneratePassphrase returned error: %v", err)
+	}
+	parts := strings.Split(passphrase, " ")
+	if len(parts) != 6 {
+		t.Errorf("GeneratePassphrase = %q; expected 6 words", passphrase)
+	}
+	for _, part := range parts {
+		if !known[part] {
+			t.Errorf("GeneratePassphrase produced unknown word %q", part)
+		}
+	}
+
+	if _, err := GeneratePassphrase(0, " "); !errors.Is(err, ErrInvalidGenerator) {
+		t.Errorf("GeneratePassphrase(0): got %v; expected ErrInvalidGenerator", err)
+	}
+	if e := PassphraseEntropy(6); math.Abs(e-77.55) > 0.01 {
+		t.Errorf("PassphraseEntropy(6) = %v; expected about 77.55", e)
+	}
+}
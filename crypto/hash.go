@@ -0,0 +1,233 @@
+package crypto
+
+import (
+	"bufio"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cespare/xxhash/v2"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+	"lukechampine.com/blake3"
+)
+
+// HashAlgorithm identifies a hash function for the streaming hash helpers
+type HashAlgorithm string
+
+// Supported hash functions. MD5 and SHA1 are only suitable for checksums
+// where no attacker is involved; XXH64 and CRC32 are fast non-cryptographic
+// checksums.
+const (
+	MD5        HashAlgorithm = "md5"
+	SHA1       HashAlgorithm = "sha1"
+	SHA256     HashAlgorithm = "sha256"
+	SHA384     HashAlgorithm = "sha384"
+	SHA512     HashAlgorithm = "sha512"
+	SHA3_256   HashAlgorithm = "sha3-256"
+	SHA3_512   HashAlgorithm = "sha3-512"
+	BLAKE2b256 HashAlgorithm = "blake2b-256"
+	BLAKE2b512 HashAlgorithm = "blake2b-512"
+	BLAKE3     HashAlgorithm = "blake3"
+	XXH64      HashAlgorithm = "xxh64"
+	CRC32      HashAlgorithm = "crc32"
+)
+
+// NewHash returns a new hash.Hash computing alg
+func NewHash(alg HashAlgorithm) (hash.Hash, error) {
+	switch alg {
+	case MD5:
+		return md5.New(), nil
+	case SHA1:
+		return sha1.New(), nil
+	case SHA256:
+		return sha256.New(), nil
+	case SHA384:
+		return sha512.New384(), nil
+	case SHA512:
+		return sha512.New(), nil
+	case SHA3_256:
+		return sha3.New256(), nil
+	case SHA3_512:
+		return sha3.New512(), nil
+	case BLAKE2b256:
+		return blake2b.New256(nil)
+	case BLAKE2b512:
+		return blake2b.New512(nil)
+	case BLAKE3:
+		return blake3.New(32, nil), nil
+	case XXH64:
+		return xxhash.New(), nil
+	case CRC32:
+		return crc32.NewIEEE(), nil
+	}
+	return nil, fmt.Errorf("crypto: unsupported hash algorithm %q", alg)
+}
+
+// HashReader reads r to the end and returns its alg digest in hex
+func HashReader(r io.Reader, alg HashAlgorithm) (string, error) {
+	sums, err := HashReaderMulti(r, alg)
+	if err != nil {
+		return "", err
+	}
+	return sums[alg], nil
+}
+
+// HashReaderMulti reads r once and returns the hex digest of every
+// algorithm in algs
+func HashReaderMulti(r io.Reader, algs ...HashAlgorithm) (map[HashAlgorithm]string, error) {
+	hashes := make([]hash.Hash, len(algs))
+	writers := make([]io.Writer, len(algs))
+	for i, alg := range algs {
+		h, err := NewHash(alg)
+		if err != nil {
+			return nil, err
+		}
+		hashes[i], writers[i] = h, h
+	}
+
+	if _, err := io.Copy(io.MultiWriter(writers...), bufio.NewReaderSize(r, 64*1024)); err != nil {
+		return nil, err
+	}
+
+	sums := make(map[HashAlgorithm]string, len(algs))
+	for i, alg := range algs {
+		sums[alg] = hex.EncodeToString(hashes[i].Sum(nil))
+	}
+	return sums, nil
+}
+
+// HashFile returns the alg digest of the file at path in hex, reading it
+// in a single streaming pass
+func HashFile(path string, alg HashAlgorithm) (string, error) {
+	sums, err := HashFileMulti(path, alg)
+	if err != nil {
+		return "", err
+	}
+	return sums[alg], nil
+}
+
+// HashFileMulti reads the file at path once and returns the hex digest of
+// every algorithm in algs
+func HashFileMulti(path string, algs ...HashAlgorithm) (map[HashAlgorithm]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return HashReaderMulti(f, algs...)
+}
+
+// ChecksumEntry is a line of a checksum file in the format of sha256sum and
+// its siblings: "<hex digest>  <path>", or with "*" before the path for
+// binary mode
+type ChecksumEntry struct {
+	Digest string
+	Path   string
+	Binary bool
+}
+
+// ChecksumResult is the outcome of checking one entry of a checksum file.
+// Err is set when the file could not be read.
+type ChecksumResult struct {
+	Path string
+	OK   bool
+	Err  error
+}
+
+// ParseChecksums reads checksum lines from r. Blank lines and lines
+// starting with "#" are skipped. Paths escaped by sha256sum, marked with a
+// leading backslash, are unescaped.
+func ParseChecksums(r io.Reader) ([]ChecksumEntry, error) {
+	var entries []ChecksumEntry
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		escaped := strings.HasPrefix(text, `\`)
+		if escaped {
+			text = text[1:]
+		}
+		digest, rest, ok := strings.Cut(text, " ")
+		if !ok || len(rest) < 2 || (rest[0] != ' ' && rest[0] != '*') {
+			return nil, fmt.Errorf("crypto: invalid checksum line %d", line)
+		}
+		if _, err := hex.DecodeString(digest); err != nil || digest == "" {
+			return nil, fmt.Errorf("crypto: invalid digest on checksum line %d", line)
+		}
+
+		path := rest[1:]
+		if escaped {
+			path = strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\r`, "\r").Replace(path)
+		}
+		entries = append(entries, ChecksumEntry{Digest: strings.ToLower(digest), Path: path, Binary: rest[0] == '*'})
+	}
+	return entries, scanner.Err()
+}
+
+// WriteChecksums hashes each file and writes its checksum line to w in
+// the sha256sum format, using the paths as given
+func WriteChecksums(w io.Writer, alg HashAlgorithm, paths ...string) error {
+	for _, path := range paths {
+		sum, err := HashFile(path, alg)
+		if err != nil {
+			return err
+		}
+		prefix, name := "", path
+		if strings.ContainsAny(name, "\\\n\r") {
+			prefix = `\`
+			name = strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`).Replace(name)
+		}
+		if _, err := fmt.Fprintf(w, "%s%s  %s\n", prefix, sum, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyChecksums checks every file listed in the checksum file at
+// checksumPath against its alg digest. Unlike "sha256sum -c", which uses
+// the current directory, relative paths are resolved against the directory
+// of the checksum file, so a SHA256SUMS file can be checked from anywhere.
+// The error is only set when the checksum file itself cannot be read or
+// parsed.
+func VerifyChecksums(checksumPath string, alg HashAlgorithm) ([]ChecksumResult, error) {
+	f, err := os.Open(checksumPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	entries, err := ParseChecksums(f)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(checksumPath)
+	results := make([]ChecksumResult, len(entries))
+	for i, entry := range entries {
+		results[i].Path = entry.Path
+		path := entry.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		sum, err := HashFile(path, alg)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].OK = sum == entry.Digest
+	}
+	return results, nil
+}
@@ -0,0 +1,131 @@
+package crypto
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHashReader(t *testing.T) {
+	tests := []struct {
+		alg      HashAlgorithm
+		input    string
+		expected string
+	}{
+		{MD5, "abc", "900150983cd24fb0d6963f7d28e17f72"},
+		{SHA1, "abc", "a9993e364706816aba3e25717850c26c9cd0d89d"},
+		{SHA256, "abc", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{SHA384, "abc", "cb00753f45a35e8bb5a03d699ac65007272c32ab0eded1631a8b605a43ff5bed8086072ba1e7cc2358baeca134c825a7"},
+		{SHA512, "abc", "ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f"},
+		{SHA3_256, "abc", "3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532"},
+		{SHA3_512, "abc", "b751850b1a57168a5693cd924b6b096e08f621827444f70d884f5d0240d2712e10e116e9192af3c91a7ec57647e3934057340b4cf408d5a56592f8274eec53f0"},
+		{BLAKE2b256, "abc", "bddd813c634239723171ef3fee98579b94964e3bb1cb3e427262c8c068d52319"},
+		{BLAKE2b512, "abc", "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923"},
+		{BLAKE3, "", "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"},
+		{XXH64, "", "ef46db3751d8e999"},
+		{XXH64, "abc", "44bc2cf5ad770999"},
+		{CRC32, "abc", "352441c2"},
+	}
+
+	for _, test := range tests {
+		result, err := HashReader(strings.NewReader(test.input), test.alg)
+		if err != nil {
+			t.Errorf("HashReader(%q, %s) returned error: %v", test.input, test.alg, err)
+			continue
+		}
+		if result != test.expected {
+			t.Errorf("HashReader(%q, %s) = %s; expected %s", test.input, test.alg, result, test.expected)
+		}
+	}
+
+	if _, err := HashReader(strings.NewReader(""), "md4"); err == nil {
+		t.Error("HashReader should reject an unknown algorithm")
+	}
+}
+
+func TestHashReaderMultiMatchesSingle(t *testing.T) {
+	data := bytes.Repeat([]byte("artifact-"), 100000)
+	algs := []HashAlgorithm{SHA256, BLAKE3, XXH64, MD5}
+
+	sums, err := HashReaderMulti(bytes.NewReader(data), algs...)
+	if err != nil {
+		t.Fatalf("HashReaderMulti returned error: %v", err)
+	}
+	for _, alg := range algs {
+		single, _ := HashReader(bytes.NewReader(data), alg)
+		if sums[alg] != single {
+			t.Errorf("HashReaderMulti %s = %s; HashReader gives %s", alg, sums[alg], single)
+		}
+	}
+	if sums[SHA256] != SHA256Hash(string(data)) {
+		t.Error("HashReaderMulti SHA-256 does not match SHA256Hash")
+	}
+}
+
+func TestChecksumFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{"app.tar.gz": "binary contents", "notes.txt": "release notes\n"}
+	var paths []string
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte(content), 0644)
+		paths = append(paths, path)
+	}
+
+	var sums bytes.Buffer
+	if err := WriteChecksums(&sums, SHA256, paths...); err != nil {
+		t.Fatalf("WriteChecksums returned error: %v", err)
+	}
+	// Compatible with sha256sum: digest, two spaces, path
+	if !strings.Contains(sums.String(), SHA256Hash("binary contents")+"  "+filepath.Join(dir, "app.tar.gz")+"\n") {
+		t.Errorf("WriteChecksums wrote %q", sums.String())
+	}
+
+	// A binary mode line, a comment and a missing file
+	sums.WriteString("# generated\n" + SHA256Hash("other") + " *missing.bin\n")
+	sumsPath := filepath.Join(dir, "SHA256SUMS")
+	os.WriteFile(sumsPath, sums.Bytes(), 0644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("tampered\n"), 0644)
+
+	results, err := VerifyChecksums(sumsPath, SHA256)
+	if err != nil {
+		t.Fatalf("VerifyChecksums returned error: %v", err)
+	}
+	status := map[string]string{}
+	for _, r := range results {
+		r.Path = filepath.Base(r.Path)
+		switch {
+		case r.Err != nil:
+			status[r.Path] = "error"
+		case r.OK:
+			status[r.Path] = "ok"
+		default:
+			status[r.Path] = "mismatch"
+		}
+	}
+	expected := map[string]string{"app.tar.gz": "ok", "notes.txt": "mismatch", "missing.bin": "error"}
+	for path, want := range expected {
+		if status[path] != want {
+			t.Errorf("VerifyChecksums %s = %s; expected %s", path, status[path], want)
+		}
+	}
+}
+
+func TestParseChecksums(t *testing.T) {
+	input := "d41d8cd98f00b204e9800998ecf8427e  empty.txt\r\n\\d41d8cd98f00b204e9800998ecf8427e  odd\\\\name\n"
+	entries, err := ParseChecksums(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseChecksums returned error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Path != "empty.txt" || entries[1].Path != `odd\name` {
+		t.Errorf("ParseChecksums = %+v", entries)
+	}
+
+	for _, bad := range []string{"nothex  file\n", "abc\n", "abcd file\n"} {
+		if _, err := ParseChecksums(strings.NewReader(bad)); err == nil {
+			t.Errorf("ParseChecksums(%q) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// JWE algorithms used by EncryptJWT: direct encryption with a shared key,
+// so there is no encrypted key, and AES-256-GCM for the content. Key
+// wrapping and key agreement algorithms are not supported.
+const (
+	jweAlgorithm  = "dir"
+	jweEncryption = "A256GCM"
+	jweIVSize     = 12
+	jweTagSize    = 16
+)
+
+// jweHeader is the protected JOSE header of an encrypted token
+type jweHeader struct {
+	Algorithm  string   `json:"alg"`
+	Encryption string   `json:"enc"`
+	Type       string   `json:"typ,omitempty"`
+	KeyID      string   `json:"kid,omitempty"`
+	Critical   []string `json:"crit,omitempty"`
+}
+
+// EncryptJWT creates an encrypted token in the JWE compact serialization,
+// with "alg":"dir" and "enc":"A256GCM". key is the 32-byte secret shared
+// with the recipient. keyID, if set, becomes the "kid" header so the
+// recipient can pick the key from a JWKSet.
+func EncryptJWT(claims Claims, key []byte, keyID string) (string, error) {
+	gcm, err := newJWEAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	header, err := json.Marshal(jweHeader{Algorithm: jweAlgorithm, Encryption: jweEncryption, Type: "JWT", KeyID: keyID})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	iv, err := RandomBytes(jweIVSize)
+	if err != nil {
+		return "", err
+	}
+
+	// The encoded protected header is the additional authenticated data
+	protected := tokenEncoding.EncodeToString(header)
+	sealed := gcm.Seal(nil, iv, payload, []byte(protected))
+	ciphertext, tag := sealed[:len(sealed)-jweTagSize], sealed[len(sealed)-jweTagSize:]
+	return strings.Join([]string{
+		protected,
+		"",
+		tokenEncoding.EncodeToString(iv),
+		tokenEncoding.EncodeToString(ciphertext),
+		tokenEncoding.EncodeToString(tag),
+	}, "."), nil
+}
+
+// DecryptJWT decrypts a token from EncryptJWT and validates its claims as
+// VerifyJWT does. v.Key or the key named by "kid" in v.KeySet must be the
+// 32-byte shared secret; v.Algorithms does not apply. A token that fails
+// authentication returns ErrJWTSignature.
+func DecryptJWT(token string, v JWTValidation) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 5 || parts[1] != "" {
+		return nil, ErrJWTMalformed
+	}
+	rawHeader, err := tokenEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrJWTMalformed
+	}
+	var header jweHeader
+	if err := json.Unmarshal(rawHeader, &header); err != nil {
+		return nil, ErrJWTMalformed
+	}
+	if len(header.Critical) > 0 {
+		return nil, fmt.Errorf("%w: unsupported critical header %q", ErrJWTMalformed, header.Critical)
+	}
+	if header.Algorithm != jweAlgorithm || header.Encryption != jweEncryption {
+		return nil, fmt.Errorf("%w: %q with %q", ErrJWTAlgorithm, header.Algorithm, header.Encryption)
+	}
+
+	key := v.Key
+	if v.KeySet != nil {
+		jwk, ok := v.KeySet.Lookup(header.KeyID)
+		if !ok {
+			return nil, fmt.Errorf("%w: no key for kid %q", ErrJWTSignature, header.KeyID)
+		}
+		if jwk.Algorithm != "" && jwk.Algorithm != jweAlgorithm && jwk.Algorithm != jweEncryption {
+			return nil, fmt.Errorf("%w: key %q is for %s", ErrJWTAlgorithm, jwk.KeyID, jwk.Algorithm)
+		}
+		key = jwk.Key
+	}
+	secret, ok := key.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s needs a []byte secret", ErrJWTAlgorithm, jweEncryption)
+	}
+	gcm, err := newJWEAEAD(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	iv, err := tokenEncoding.DecodeString(parts[2])
+	if err != nil || len(iv) != jweIVSize {
+		return nil, ErrJWTMalformed
+	}
+	ciphertext, err := tokenEncoding.DecodeString(parts[3])
+	if err != nil {
+		return nil, ErrJWTMalformed
+	}
+	tag, err := tokenEncoding.DecodeString(parts[4])
+	if err != nil || len(tag) != jweTagSize {
+		return nil, ErrJWTMalformed
+	}
+	payload, err := gcm.Open(nil, iv, append(ciphertext, tag...), []byte(parts[0]))
+	if err != nil {
+		return nil, ErrJWTSignature
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrJWTMalformed
+	}
+	if err := v.validateClaims(&claims); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+// newJWEAEAD returns the A256GCM cipher for a 32-byte key
+func newJWEAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("%w: %s needs a 32-byte key, got %d bytes", ErrJWTAlgorithm, jweEncryption, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
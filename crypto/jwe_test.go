@@ -0,0 +1,89 @@
+package crypto
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncryptJWT(t *testing.T) {
+	key, _ := NewEncryptionKey()
+	claims := Claims{Subject: "42", Audience: Audience{"api"}, ExpiresAt: time.Now().Add(time.Hour).Truncate(time.Second).UTC(),
+		Custom: map[string]interface{}{"role": "admin"}}
+
+	token, err := EncryptJWT(claims, key, "enc-1")
+	if err != nil {
+		t.Fatalf("EncryptJWT returned error: %v", err)
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 5 || parts[1] != "" {
+		t.Fatalf("EncryptJWT = %q; expected five parts with an empty encrypted key", token)
+	}
+	if strings.Contains(token, tokenEncoding.EncodeToString([]byte(`"sub":"42"`))) {
+		t.Error("EncryptJWT leaves the claims readable")
+	}
+
+	decrypted, err := DecryptJWT(token, JWTValidation{Key: key, Audience: "api"})
+	if err != nil {
+		t.Fatalf("DecryptJWT returned error: %v", err)
+	}
+	if decrypted.Subject != "42" || !decrypted.ExpiresAt.Equal(claims.ExpiresAt) || decrypted.Custom["role"] != "admin" {
+		t.Errorf("DecryptJWT = %+v; expected %+v", decrypted, claims)
+	}
+
+	set, _ := ParseJWKSet([]byte(`{"keys":[{"kty":"oct","kid":"enc-1","alg":"dir","k":"` + tokenEncoding.EncodeToString(key) + `"}]}`))
+	if _, err := DecryptJWT(token, JWTValidation{KeySet: set}); err != nil {
+		t.Errorf("DecryptJWT with a key set returned error: %v", err)
+	}
+}
+
+func TestDecryptJWTRejects(t *testing.T) {
+	key, _ := NewEncryptionKey()
+	other, _ := NewEncryptionKey()
+	token, _ := EncryptJWT(Claims{Subject: "42"}, key, "")
+	expired, _ := EncryptJWT(Claims{ExpiresAt: time.Now().Add(-time.Hour)}, key, "")
+	parts := strings.Split(token, ".")
+	flip := func(i int) string {
+		p := append([]string(nil), parts...)
+		raw, _ := tokenEncoding.DecodeString(p[i])
+		raw[0] ^= 0x01
+		p[i] = tokenEncoding.EncodeToString(raw)
+		return strings.Join(p, ".")
+	}
+	withHeader := func(header string) string {
+		p := append([]string(nil), parts...)
+		p[0] = tokenEncoding.EncodeToString([]byte(header))
+		return strings.Join(p, ".")
+	}
+	signed, _ := SignJWT(Claims{Subject: "42"}, HS256, key, "")
+
+	tests := []struct {
+		name     string
+		token    string
+		key      interface{}
+		expected error
+	}{
+		{"wrong key", token, other, ErrJWTSignature},
+		{"altered header", flip(0), key, ErrJWTMalformed},
+		{"altered iv", flip(2), key, ErrJWTSignature},
+		{"altered ciphertext", flip(3), key, ErrJWTSignature},
+		{"altered tag", flip(4), key, ErrJWTSignature},
+		{"key wrapping", withHeader(`{"alg":"A256KW","enc":"A256GCM"}`), key, ErrJWTAlgorithm},
+		{"none", withHeader(`{"alg":"none","enc":"A256GCM"}`), key, ErrJWTAlgorithm},
+		{"short key", token, key[:16], ErrJWTAlgorithm},
+		{"public key", token, "not a secret", ErrJWTAlgorithm},
+		{"signed token", signed, key, ErrJWTMalformed},
+		{"expired", expired, key, ErrJWTExpired},
+	}
+
+	for _, test := range tests {
+		if _, err := DecryptJWT(test.token, JWTValidation{Key: test.key}); !errors.Is(err, test.expected) {
+			t.Errorf("%s: DecryptJWT returned %v; expected %v", test.name, err, test.expected)
+		}
+	}
+
+	if _, err := VerifyJWT(token, JWTValidation{Key: key}); !errors.Is(err, ErrJWTMalformed) {
+		t.Errorf("VerifyJWT of an encrypted token: got %v; expected ErrJWTMalformed", err)
+	}
+}
@@ -0,0 +1,599 @@
+package crypto
+
+import (
+	"bytes"
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	utime "github.com/yourusername/goutils/time"
+)
+
+// JWTAlgorithm is a JWS signature algorithm as named in the "alg" header
+type JWTAlgorithm string
+
+// Supported JWT signature algorithms. "none" is deliberately absent and
+// always rejected.
+const (
+	HS256 JWTAlgorithm = "HS256"
+	HS384 JWTAlgorithm = "HS384"
+	HS512 JWTAlgorithm = "HS512"
+	RS256 JWTAlgorithm = "RS256"
+	ES256 JWTAlgorithm = "ES256"
+	EdDSA JWTAlgorithm = "EdDSA"
+)
+
+var (
+	// ErrJWTMalformed is returned for tokens that are not three base64url
+	// segments holding a valid header and claims
+	ErrJWTMalformed = errors.New("crypto: malformed JWT")
+	// ErrJWTAlgorithm is returned for "none", unknown algorithms, algorithms
+	// not allowed by the validation and keys of the wrong type for the
+	// algorithm, which is how algorithm confusion attacks are stopped
+	ErrJWTAlgorithm = errors.New("crypto: JWT algorithm not accepted")
+	// ErrJWTSignature is returned when the signature does not verify or an
+	// encrypted token fails authentication
+	ErrJWTSignature = errors.New("crypto: JWT signature is invalid")
+	// ErrJWTExpired is returned for tokens past their "exp" claim
+	ErrJWTExpired = errors.New("crypto: JWT is expired")
+	// ErrJWTNotYetValid is returned for tokens before their "nbf" claim or
+	// issued in the future
+	ErrJWTNotYetValid = errors.New("crypto: JWT is not valid yet")
+	// ErrJWTClaims is returned when issuer, audience or required claims do
+	// not match the validation
+	ErrJWTClaims = errors.New("crypto: JWT claims rejected")
+)
+
+// Audience is the "aud" claim, which JSON may hold as a string or an array
+type Audience []string
+
+// MarshalJSON encodes a single audience as a plain string
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// UnmarshalJSON accepts a string or an array of strings
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+// Claims are the claims of a JWT. Zero values are left out of the token.
+type Claims struct {
+	Issuer    string
+	Subject   string
+	Audience  Audience
+	ExpiresAt time.Time
+	NotBefore time.Time
+	IssuedAt  time.Time
+	ID        string
+	// Custom holds every other claim
+	Custom map[string]interface{}
+}
+
+// registeredClaims is the JSON form of the registered claims. Numeric
+// dates are kept as json.Number since they may carry fractions.
+type registeredClaims struct {
+	Issuer    string      `json:"iss,omitempty"`
+	Subject   string      `json:"sub,omitempty"`
+	Audience  Audience    `json:"aud,omitempty"`
+	ExpiresAt json.Number `json:"exp,omitempty"`
+	NotBefore json.Number `json:"nbf,omitempty"`
+	IssuedAt  json.Number `json:"iat,omitempty"`
+	ID        string      `json:"jti,omitempty"`
+}
+
+var registeredClaimNames = []string{"iss", "sub", "aud", "exp", "nbf", "iat", "jti"}
+
+// MarshalJSON encodes the registered claims together with Custom
+func (c Claims) MarshalJSON() ([]byte, error) {
+	out := make(map[string]interface{}, len(c.Custom)+7)
+	for k, v := range c.Custom {
+		out[k] = v
+	}
+	for _, name := range registeredClaimNames {
+		delete(out, name)
+	}
+	set := func(name string, value interface{}, present bool) {
+		if present {
+			out[name] = value
+		}
+	}
+	set("iss", c.Issuer, c.Issuer != "")
+	set("sub", c.Subject, c.Subject != "")
+	set("aud", c.Audience, len(c.Audience) > 0)
+	set("exp", c.ExpiresAt.Unix(), !c.ExpiresAt.IsZero())
+	set("nbf", c.NotBefore.Unix(), !c.NotBefore.IsZero())
+	set("iat", c.IssuedAt.Unix(), !c.IssuedAt.IsZero())
+	set("jti", c.ID, c.ID != "")
+	return json.Marshal(out)
+}
+
+// maxNumericDate is the last second of the year 9999, the latest "exp",
+// "nbf" or "iat" accepted
+const maxNumericDate = 253402300799
+
+// UnmarshalJSON decodes the registered claims and keeps the rest in Custom
+func (c *Claims) UnmarshalJSON(data []byte) error {
+	var reg registeredClaims
+	if err := json.Unmarshal(data, &reg); err != nil {
+		return err
+	}
+	// Custom claims keep numbers as json.Number so large IDs stay exact
+	var all map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&all); err != nil {
+		return err
+	}
+	for _, name := range registeredClaimNames {
+		delete(all, name)
+	}
+
+	*c = Claims{Issuer: reg.Issuer, Subject: reg.Subject, Audience: reg.Audience, ID: reg.ID}
+	if len(all) > 0 {
+		c.Custom = all
+	}
+	for _, d := range []struct {
+		n   json.Number
+		dst *time.Time
+	}{{reg.ExpiresAt, &c.ExpiresAt}, {reg.NotBefore, &c.NotBefore}, {reg.IssuedAt, &c.IssuedAt}} {
+		if d.n == "" {
+			continue
+		}
+		// Out of range values would wrap around when converted to seconds
+		f, err := strconv.ParseFloat(string(d.n), 64)
+		if err != nil || math.IsNaN(f) || f < 0 || f > maxNumericDate {
+			return fmt.Errorf("invalid numeric date %q", d.n)
+		}
+		*d.dst = time.Unix(int64(f), 0).UTC()
+	}
+	return nil
+}
+
+// jwtHeader is the JOSE header of a token
+type jwtHeader struct {
+	Algorithm JWTAlgorithm `json:"alg"`
+	Type      string       `json:"typ,omitempty"`
+	KeyID     string       `json:"kid,omitempty"`
+	Critical  []string     `json:"crit,omitempty"`
+}
+
+// SignJWT creates a signed token. key must suit alg: a []byte secret for
+// the HS algorithms, *rsa.PrivateKey for RS256, a P-256 *ecdsa.PrivateKey
+// for ES256 and ed25519.PrivateKey for EdDSA. keyID, if set, becomes the
+// "kid" header so verifiers can pick the key from a JWKSet.
+func SignJWT(claims Claims, alg JWTAlgorithm, key interface{}, keyID string) (string, error) {
+	header, err := json.Marshal(jwtHeader{Algorithm: alg, Type: "JWT", KeyID: keyID})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := tokenEncoding.EncodeToString(header) + "." + tokenEncoding.EncodeToString(payload)
+	signature, err := jwtSign(alg, key, []byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + tokenEncoding.EncodeToString(signature), nil
+}
+
+func jwtHash(alg JWTAlgorithm) (func() hash.Hash, bool) {
+	switch alg {
+	case HS256:
+		return sha256.New, true
+	case HS384:
+		return sha512.New384, true
+	case HS512:
+		return sha512.New, true
+	}
+	return nil, false
+}
+
+func jwtSign(alg JWTAlgorithm, key interface{}, input []byte) ([]byte, error) {
+	if h, ok := jwtHash(alg); ok {
+		secret, ok := key.([]byte)
+		if !ok || len(secret) == 0 {
+			return nil, fmt.Errorf("%w: %s needs a []byte secret", ErrJWTAlgorithm, alg)
+		}
+		mac := hmac.New(h, secret)
+		mac.Write(input)
+		return mac.Sum(nil), nil
+	}
+
+	switch alg {
+	case RS256:
+		k, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: RS256 needs an *rsa.PrivateKey", ErrJWTAlgorithm)
+		}
+		digest := sha256.Sum256(input)
+		return rsa.SignPKCS1v15(rand.Reader, k, stdcrypto.SHA256, digest[:])
+	case ES256:
+		k, ok := key.(*ecdsa.PrivateKey)
+		if !ok || k.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("%w: ES256 needs a P-256 *ecdsa.PrivateKey", ErrJWTAlgorithm)
+		}
+		digest := sha256.Sum256(input)
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			return nil, err
+		}
+		// JWS uses the fixed-size concatenation of r and s, not ASN.1
+		signature := make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+		return signature, nil
+	case EdDSA:
+		k, ok := key.(ed25519.PrivateKey)
+		if !ok || len(k) != ed25519.PrivateKeySize {
+			return nil, fmt.Errorf("%w: EdDSA needs an ed25519.PrivateKey", ErrJWTAlgorithm)
+		}
+		return ed25519.Sign(k, input), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrJWTAlgorithm, alg)
+}
+
+// jwtVerify checks signature with a public key or secret of the type alg
+// requires. Keys of any other type are rejected, so an RSA public key can
+// never be used as an HMAC secret.
+func jwtVerify(alg JWTAlgorithm, key interface{}, input, signature []byte) error {
+	if h, ok := jwtHash(alg); ok {
+		secret, ok := key.([]byte)
+		if !ok || len(secret) == 0 {
+			return ErrJWTAlgorithm
+		}
+		mac := hmac.New(h, secret)
+		mac.Write(input)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return ErrJWTSignature
+		}
+		return nil
+	}
+
+	switch alg {
+	case RS256:
+		k, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return ErrJWTAlgorithm
+		}
+		digest := sha256.Sum256(input)
+		if rsa.VerifyPKCS1v15(k, stdcrypto.SHA256, digest[:], signature) != nil {
+			return ErrJWTSignature
+		}
+		return nil
+	case ES256:
+		k, ok := key.(*ecdsa.PublicKey)
+		if !ok || k.Curve != elliptic.P256() {
+			return ErrJWTAlgorithm
+		}
+		if len(signature) != 64 {
+			return ErrJWTSignature
+		}
+		digest := sha256.Sum256(input)
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(k, digest[:], r, s) {
+			return ErrJWTSignature
+		}
+		return nil
+	case EdDSA:
+		k, ok := key.(ed25519.PublicKey)
+		if !ok || len(k) != ed25519.PublicKeySize {
+			return ErrJWTAlgorithm
+		}
+		if !ed25519.Verify(k, input, signature) {
+			return ErrJWTSignature
+		}
+		return nil
+	}
+	return ErrJWTAlgorithm
+}
+
+// JWTValidation describes what VerifyJWT accepts
+type JWTValidation struct {
+	// Key is the secret or public key used when KeySet is nil
+	Key interface{}
+	// KeySet supplies the key named by the token's "kid" header
+	KeySet *JWKSet
+	// Algorithms, when not empty, limits the accepted algorithms. The key
+	// type must always match the algorithm.
+	Algorithms []JWTAlgorithm
+	// Issuer and Audience, when set, must match the "iss" claim and be one
+	// of the "aud" claim values
+	Issuer   string
+	Audience string
+	// RequireExpiry rejects tokens without an "exp" claim
+	RequireExpiry bool
+	// Leeway tolerates clock skew between the issuer and this machine
+	Leeway time.Duration
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+}
+
+// VerifyJWT checks the signature and claims of token and returns the
+// claims. Errors wrap one of the ErrJWT values.
+func VerifyJWT(token string, v JWTValidation) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrJWTMalformed
+	}
+	rawHeader, err := tokenEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrJWTMalformed
+	}
+	var header jwtHeader
+	if err := json.Unmarshal(rawHeader, &header); err != nil {
+		return nil, ErrJWTMalformed
+	}
+	if len(header.Critical) > 0 {
+		return nil, fmt.Errorf("%w: unsupported critical header %q", ErrJWTMalformed, header.Critical)
+	}
+
+	if strings.EqualFold(string(header.Algorithm), "none") {
+		return nil, fmt.Errorf("%w: %q", ErrJWTAlgorithm, header.Algorithm)
+	}
+	if len(v.Algorithms) > 0 && !containsAlgorithm(v.Algorithms, header.Algorithm) {
+		return nil, fmt.Errorf("%w: %q is not allowed", ErrJWTAlgorithm, header.Algorithm)
+	}
+
+	key := v.Key
+	if v.KeySet != nil {
+		jwk, ok := v.KeySet.Lookup(header.KeyID)
+		if !ok {
+			return nil, fmt.Errorf("%w: no key for kid %q", ErrJWTSignature, header.KeyID)
+		}
+		if jwk.Algorithm != "" && JWTAlgorithm(jwk.Algorithm) != header.Algorithm {
+			return nil, fmt.Errorf("%w: key %q is for %s", ErrJWTAlgorithm, jwk.KeyID, jwk.Algorithm)
+		}
+		key = jwk.Key
+	}
+
+	signature, err := tokenEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrJWTMalformed
+	}
+	if err := jwtVerify(header.Algorithm, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	payload, err := tokenEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrJWTMalformed
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrJWTMalformed
+	}
+	if err := v.validateClaims(&claims); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+func containsAlgorithm(list []JWTAlgorithm, alg JWTAlgorithm) bool {
+	for _, a := range list {
+		if a == alg {
+			return true
+		}
+	}
+	return false
+}
+
+func (v JWTValidation) validateClaims(c *Claims) error {
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+
+	if c.ExpiresAt.IsZero() {
+		if v.RequireExpiry {
+			return fmt.Errorf("%w: missing exp", ErrJWTClaims)
+		}
+	} else if utime.IsExpired(c.ExpiresAt, now, v.Leeway) {
+		return ErrJWTExpired
+	}
+	if !c.NotBefore.IsZero() && utime.IsNotYetValid(c.NotBefore, now, v.Leeway) {
+		return ErrJWTNotYetValid
+	}
+	if !c.IssuedAt.IsZero() && utime.IsNotYetValid(c.IssuedAt, now, v.Leeway) {
+		return fmt.Errorf("%w: issued in the future", ErrJWTNotYetValid)
+	}
+
+	if v.Issuer != "" && c.Issuer != v.Issuer {
+		return fmt.Errorf("%w: issuer %q", ErrJWTClaims, c.Issuer)
+	}
+	if v.Audience != "" {
+		found := false
+		for _, aud := range c.Audience {
+			found = found || aud == v.Audience
+		}
+		if !found {
+			return fmt.Errorf("%w: audience %q", ErrJWTClaims, []string(c.Audience))
+		}
+	}
+	return nil
+}
+
+// JWK is a public key or shared secret from a JSON Web Key (RFC 7517). Key
+// holds *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey or, for
+// symmetric keys, []byte. Private key members are ignored.
+type JWK struct {
+	KeyID     string
+	Algorithm string
+	Use       string
+	Key       interface{}
+}
+
+// JWKSet is a JSON Web Key Set as served from a jwks_uri
+type JWKSet struct {
+	Keys []JWK
+}
+
+// rawJWK is the JSON form of a JWK
+type rawJWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	Curve     string `json:"crv"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+	K         string `json:"k"`
+}
+
+// ParseJWK parses a single JSON Web Key
+func ParseJWK(data []byte) (*JWK, error) {
+	var raw rawJWK
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("crypto: invalid JWK: %w", err)
+	}
+	return raw.parse()
+}
+
+// ParseJWKSet parses a JSON Web Key Set. Keys of unsupported types are
+// skipped, as RFC 7517 requires, but malformed keys are an error.
+func ParseJWKSet(data []byte) (*JWKSet, error) {
+	var raw struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("crypto: invalid JWK set: %w", err)
+	}
+
+	set := &JWKSet{}
+	for _, k := range raw.Keys {
+		var r rawJWK
+		if err := json.Unmarshal(k, &r); err != nil {
+			return nil, fmt.Errorf("crypto: invalid JWK: %w", err)
+		}
+		jwk, err := r.parse()
+		if errors.Is(err, errUnsupportedJWK) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		set.Keys = append(set.Keys, *jwk)
+	}
+	return set, nil
+}
+
+// Lookup returns the key with the given ID. A token without "kid" matches
+// a set holding a single key.
+func (s *JWKSet) Lookup(keyID string) (JWK, bool) {
+	if keyID == "" && len(s.Keys) == 1 {
+		return s.Keys[0], true
+	}
+	for _, k := range s.Keys {
+		if k.KeyID == keyID && keyID != "" {
+			return k, true
+		}
+	}
+	return JWK{}, false
+}
+
+var errUnsupportedJWK = errors.New("crypto: unsupported JWK type")
+
+func (r rawJWK) parse() (*JWK, error) {
+	jwk := &JWK{KeyID: r.KeyID, Algorithm: r.Algorithm, Use: r.Use}
+	decode := func(field, value string) ([]byte, error) {
+		b, err := tokenEncoding.DecodeString(value)
+		if err != nil || len(b) == 0 {
+			return nil, fmt.Errorf("crypto: invalid JWK member %q", field)
+		}
+		return b, nil
+	}
+
+	switch r.KeyType {
+	case "RSA":
+		n, err := decode("n", r.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode("e", r.E)
+		if err != nil {
+			return nil, err
+		}
+		if len(e) > 4 || len(n) < 256 {
+			return nil, fmt.Errorf("crypto: RSA JWK must have at least 2048 bits and a small exponent")
+		}
+		jwk.Key = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	case "EC":
+		var curve elliptic.Curve
+		switch r.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, errUnsupportedJWK
+		}
+		x, err := decode("x", r.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode("y", r.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, fmt.Errorf("crypto: EC JWK point is not on %s", r.Curve)
+		}
+		jwk.Key = key
+	case "OKP":
+		if r.Curve != "Ed25519" {
+			return nil, errUnsupportedJWK
+		}
+		x, err := decode("x", r.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("crypto: Ed25519 JWK must be %d bytes", ed25519.PublicKeySize)
+		}
+		jwk.Key = ed25519.PublicKey(x)
+	case "oct":
+		k, err := decode("k", r.K)
+		if err != nil {
+			return nil, err
+		}
+		jwk.Key = k
+	default:
+		return nil, errUnsupportedJWK
+	}
+	return jwk, nil
+}
@@ -0,0 +1,239 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifyJWTSpecExample(t *testing.T) {
+	// RFC 7515 appendix A.1
+	key, _ := tokenEncoding.DecodeString("AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow")
+	token := "eyJ0eXAiOiJKV1QiLA0KICJhbGciOiJIUzI1NiJ9" +
+		".eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ" +
+		".dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+
+	claims, err := VerifyJWT(token, JWTValidation{
+		Key:    key,
+		Issuer: "joe",
+		Now:    func() time.Time { return time.Unix(1300819000, 0) },
+	})
+	if err != nil {
+		t.Fatalf("VerifyJWT returned error: %v", err)
+	}
+	if claims.ExpiresAt.Unix() != 1300819380 || claims.Custom["http://example.com/is_root"] != true {
+		t.Errorf("VerifyJWT claims = %+v", claims)
+	}
+
+	if _, err := VerifyJWT(token, JWTValidation{Key: key}); !errors.Is(err, ErrJWTExpired) {
+		t.Errorf("VerifyJWT of the expired example: got %v; expected ErrJWTExpired", err)
+	}
+}
+
+func TestJWTAlgorithms(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	edPublic, edPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	secret := []byte("0123456789abcdef0123456789abcdef")
+
+	tests := []struct {
+		alg     JWTAlgorithm
+		signKey interface{}
+		verify  interface{}
+	}{
+		{HS256, secret, secret},
+		{HS384, secret, secret},
+		{HS512, secret, secret},
+		{RS256, rsaKey, &rsaKey.PublicKey},
+		{ES256, ecKey, &ecKey.PublicKey},
+		{EdDSA, edPrivate, edPublic},
+	}
+
+	claims := Claims{
+		Subject:   "user-42",
+		Audience:  Audience{"api"},
+		ExpiresAt: time.Now().Add(time.Hour),
+		Custom:    map[string]interface{}{"role": "admin"},
+	}
+	for _, test := range tests {
+		token, err := SignJWT(claims, test.alg, test.signKey, "k1")
+		if err != nil {
+			t.Errorf("SignJWT(%s) returned error: %v", test.alg, err)
+			continue
+		}
+		verified, err := VerifyJWT(token, JWTValidation{Key: test.verify, Audience: "api", Algorithms: []JWTAlgorithm{test.alg}})
+		if err != nil {
+			t.Errorf("VerifyJWT(%s) returned error: %v", test.alg, err)
+			continue
+		}
+		if verified.Subject != "user-42" || verified.Custom["role"] != "admin" {
+			t.Errorf("VerifyJWT(%s) claims = %+v", test.alg, verified)
+		}
+
+		// Flipping a signature bit must be detected
+		parts := strings.Split(token, ".")
+		sig, _ := tokenEncoding.DecodeString(parts[2])
+		sig[0] ^= 1
+		forged := parts[0] + "." + parts[1] + "." + tokenEncoding.EncodeToString(sig)
+		if _, err := VerifyJWT(forged, JWTValidation{Key: test.verify}); !errors.Is(err, ErrJWTSignature) {
+			t.Errorf("VerifyJWT(%s) of a forged signature: got %v; expected ErrJWTSignature", test.alg, err)
+		}
+	}
+}
+
+func TestJWTRejectsNoneAndConfusion(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	payload := tokenEncoding.EncodeToString([]byte(`{"sub":"admin"}`))
+
+	for _, alg := range []string{"none", "None", "NONE"} {
+		header := tokenEncoding.EncodeToString([]byte(`{"alg":"` + alg + `"}`))
+		if _, err := VerifyJWT(header+"."+payload+".", JWTValidation{Key: &rsaKey.PublicKey}); !errors.Is(err, ErrJWTAlgorithm) {
+			t.Errorf("VerifyJWT with alg %q: got %v; expected ErrJWTAlgorithm", alg, err)
+		}
+	}
+
+	// The classic confusion attack signs HS256 with the RSA public key bytes
+	publicDER, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	forged, _ := SignJWT(Claims{Subject: "admin"}, HS256, publicDER, "")
+	if _, err := VerifyJWT(forged, JWTValidation{Key: &rsaKey.PublicKey}); !errors.Is(err, ErrJWTAlgorithm) {
+		t.Errorf("VerifyJWT of an HS256 token against an RSA key: got %v; expected ErrJWTAlgorithm", err)
+	}
+
+	token, _ := SignJWT(Claims{Subject: "x"}, RS256, rsaKey, "")
+	if _, err := VerifyJWT(token, JWTValidation{Key: &rsaKey.PublicKey, Algorithms: []JWTAlgorithm{ES256}}); !errors.Is(err, ErrJWTAlgorithm) {
+		t.Errorf("VerifyJWT of an algorithm outside the allow list: got %v; expected ErrJWTAlgorithm", err)
+	}
+
+	if _, err := SignJWT(Claims{}, RS256, []byte("secret"), ""); !errors.Is(err, ErrJWTAlgorithm) {
+		t.Errorf("SignJWT with a key of the wrong type: got %v; expected ErrJWTAlgorithm", err)
+	}
+}
+
+func TestJWTClaimValidation(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	tests := []struct {
+		name       string
+		claims     Claims
+		validation JWTValidation
+		expected   error
+	}{
+		{"valid", Claims{Issuer: "auth", Audience: Audience{"a", "b"}, ExpiresAt: now.Add(time.Minute)}, JWTValidation{Issuer: "auth", Audience: "b"}, nil},
+		{"expired", Claims{ExpiresAt: now.Add(-time.Minute)}, JWTValidation{}, ErrJWTExpired},
+		{"expired within leeway", Claims{ExpiresAt: now.Add(-time.Minute)}, JWTValidation{Leeway: 2 * time.Minute}, nil},
+		{"not before", Claims{NotBefore: now.Add(time.Minute)}, JWTValidation{}, ErrJWTNotYetValid},
+		{"not before within leeway", Claims{NotBefore: now.Add(time.Minute)}, JWTValidation{Leeway: 2 * time.Minute}, nil},
+		{"issued in the future", Claims{IssuedAt: now.Add(time.Hour)}, JWTValidation{}, ErrJWTNotYetValid},
+		{"missing expiry", Claims{}, JWTValidation{RequireExpiry: true}, ErrJWTClaims},
+		{"wrong issuer", Claims{Issuer: "evil"}, JWTValidation{Issuer: "auth"}, ErrJWTClaims},
+		{"wrong audience", Claims{Audience: Audience{"other"}}, JWTValidation{Audience: "api"}, ErrJWTClaims},
+	}
+
+	for _, test := range tests {
+		token, _ := SignJWT(test.claims, HS256, secret, "")
+		test.validation.Key = secret
+		test.validation.Now = clock
+		_, err := VerifyJWT(token, test.validation)
+		if !errors.Is(err, test.expected) {
+			t.Errorf("%s: VerifyJWT returned %v; expected %v", test.name, err, test.expected)
+		}
+	}
+}
+
+func TestJWTRejectsOutOfRangeDates(t *testing.T) {
+	secret := []byte("secret")
+	header := tokenEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	for _, claims := range []string{
+		`{"nbf":1e300}`,
+		`{"exp":-1}`,
+		`{"iat":9223372036854775807}`,
+		`{"exp":1e400}`,
+		`{"exp":"soon"}`,
+	} {
+		input := header + "." + tokenEncoding.EncodeToString([]byte(claims))
+		signature, _ := jwtSign(HS256, secret, []byte(input))
+		token := input + "." + tokenEncoding.EncodeToString(signature)
+		if _, err := VerifyJWT(token, JWTValidation{Key: secret}); !errors.Is(err, ErrJWTMalformed) {
+			t.Errorf("VerifyJWT with claims %s: got %v; expected ErrJWTMalformed", claims, err)
+		}
+	}
+
+	var claims Claims
+	if err := json.Unmarshal([]byte(`{"exp":253402300799.5}`), &claims); err == nil {
+		t.Error("Claims.UnmarshalJSON should reject dates after the year 9999")
+	}
+	if err := json.Unmarshal([]byte(`{"exp":1700000000.9}`), &claims); err != nil || claims.ExpiresAt.Unix() != 1700000000 {
+		t.Errorf("Claims.UnmarshalJSON of a fractional date = %v, %v", claims.ExpiresAt, err)
+	}
+}
+
+func TestJWKSet(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	edPublic, edPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	b64 := func(b []byte) string { return tokenEncoding.EncodeToString(b) }
+
+	set := fmt.Sprintf(`{"keys":[
+		{"kty":"RSA","kid":"rsa1","alg":"RS256","use":"sig","n":%q,"e":%q},
+		{"kty":"EC","kid":"ec1","crv":"P-256","x":%q,"y":%q},
+		{"kty":"OKP","kid":"ed1","crv":"Ed25519","x":%q},
+		{"kty":"oct","kid":"hs1","k":%q},
+		{"kty":"future-type","kid":"skip"}
+	]}`,
+		b64(rsaKey.N.Bytes()), b64(big.NewInt(int64(rsaKey.E)).Bytes()),
+		b64(ecKey.X.FillBytes(make([]byte, 32))), b64(ecKey.Y.FillBytes(make([]byte, 32))),
+		b64(edPublic), b64([]byte("shared-secret")))
+
+	keys, err := ParseJWKSet([]byte(set))
+	if err != nil {
+		t.Fatalf("ParseJWKSet returned error: %v", err)
+	}
+	if len(keys.Keys) != 4 {
+		t.Fatalf("ParseJWKSet returned %d keys; expected 4", len(keys.Keys))
+	}
+
+	signers := []struct {
+		alg JWTAlgorithm
+		key interface{}
+		kid string
+	}{
+		{RS256, rsaKey, "rsa1"},
+		{ES256, ecKey, "ec1"},
+		{EdDSA, edPrivate, "ed1"},
+		{HS256, []byte("shared-secret"), "hs1"},
+	}
+	for _, s := range signers {
+		token, _ := SignJWT(Claims{Subject: s.kid}, s.alg, s.key, s.kid)
+		if _, err := VerifyJWT(token, JWTValidation{KeySet: keys}); err != nil {
+			t.Errorf("VerifyJWT with kid %q returned error: %v", s.kid, err)
+		}
+	}
+
+	// The RSA key is pinned to RS256 by its "alg" member
+	token, _ := SignJWT(Claims{}, HS256, []byte("x"), "rsa1")
+	if _, err := VerifyJWT(token, JWTValidation{KeySet: keys}); !errors.Is(err, ErrJWTAlgorithm) {
+		t.Errorf("VerifyJWT against a key pinned to another alg: got %v; expected ErrJWTAlgorithm", err)
+	}
+	token, _ = SignJWT(Claims{}, HS256, []byte("x"), "unknown")
+	if _, err := VerifyJWT(token, JWTValidation{KeySet: keys}); err == nil {
+		t.Error("VerifyJWT should reject an unknown kid")
+	}
+
+	// Points off the curve are rejected
+	bad, _ := json.Marshal(map[string]string{"kty": "EC", "crv": "P-256", "x": b64([]byte{1}), "y": b64([]byte{2})})
+	if _, err := ParseJWK(bad); err == nil {
+		t.Error("ParseJWK should reject a point that is not on the curve")
+	}
+}
@@ -0,0 +1,343 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// HKDF derives a key of length bytes from high-entropy secret material
+// using HKDF-SHA256 (RFC 5869). Different info values give independent
+// keys from the same secret; salt may be nil.
+func HKDF(secret, salt, info []byte, length int) ([]byte, error) {
+	key := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, info), key); err != nil {
+		return nil, fmt.Errorf("crypto: hkdf: %w", err)
+	}
+	return key, nil
+}
+
+// PBKDF2 derives a key of length bytes from a password using
+// PBKDF2-HMAC-SHA256. Use a random salt of at least 16 bytes and, as of
+// today, at least 600000 iterations.
+func PBKDF2(password string, salt []byte, iterations, length int) []byte {
+	return pbkdf2.Key([]byte(password), salt, iterations, length, sha256.New)
+}
+
+// Key is a named secret held in a Keyring
+type Key struct {
+	ID        string
+	Material  []byte
+	Algorithm CipherAlgorithm
+	Created   time.Time
+	// Active keys are accepted for decryption and verification. The primary
+	// key is always active.
+	Active bool
+}
+
+var (
+	// ErrKeyNotFound is returned when no key has the requested ID
+	ErrKeyNotFound = errors.New("crypto: key not found")
+	// ErrNoPrimaryKey is returned when a Keyring without keys is used
+	ErrNoPrimaryKey = errors.New("crypto: keyring has no primary key")
+)
+
+// Keyring holds named keys, one of which is primary. New data is always
+// encrypted with the primary key, while data under any active key can
+// still be decrypted, so keys can be rotated without downtime: add a new
+// primary, re-encrypt at leisure, then deactivate the old key. A Keyring is
+// safe for concurrent use.
+type Keyring struct {
+	mu      sync.RWMutex
+	keys    map[string]*Key
+	primary string
+}
+
+// NewKeyring returns an empty Keyring
+func NewKeyring() *Keyring {
+	return &Keyring{keys: make(map[string]*Key)}
+}
+
+// Add stores a key under id. The first key added becomes the primary key.
+func (k *Keyring) Add(id string, material []byte, alg CipherAlgorithm) error {
+	if id == "" || len(id) > 255 {
+		return fmt.Errorf("crypto: key ID must be 1 to 255 bytes")
+	}
+	if alg == 0 {
+		alg = AES256GCM
+	}
+	if _, err := newAEAD(alg, material); err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.keys[id]; ok {
+		return fmt.Errorf("crypto: key %q already exists", id)
+	}
+	k.keys[id] = &Key{
+		ID:        id,
+		Material:  append([]byte(nil), material...),
+		Algorithm: alg,
+		Created:   time.Now().UTC(),
+		Active:    true,
+	}
+	if k.primary == "" {
+		k.primary = id
+	}
+	return nil
+}
+
+// Rotate generates a new random key under id and makes it the primary key.
+// The previous primary key stays active for decryption.
+func (k *Keyring) Rotate(id string, alg CipherAlgorithm) error {
+	material, err := NewEncryptionKey()
+	if err != nil {
+		return err
+	}
+	if err := k.Add(id, material, alg); err != nil {
+		return err
+	}
+	return k.SetPrimary(id)
+}
+
+// SetPrimary makes the key with the given id primary and active
+func (k *Keyring) SetPrimary(id string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	key, ok := k.keys[id]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	key.Active = true
+	k.primary = id
+	return nil
+}
+
+// Deactivate stops accepting the key with the given id. The primary key
+// cannot be deactivated.
+func (k *Keyring) Deactivate(id string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	key, ok := k.keys[id]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	if id == k.primary {
+		return fmt.Errorf("crypto: cannot deactivate primary key %q", id)
+	}
+	key.Active = false
+	return nil
+}
+
+// Remove deletes the key with the given id. The primary key cannot be
+// removed.
+func (k *Keyring) Remove(id string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.keys[id]; !ok {
+		return ErrKeyNotFound
+	}
+	if id == k.primary {
+		return fmt.Errorf("crypto: cannot remove primary key %q", id)
+	}
+	delete(k.keys, id)
+	return nil
+}
+
+// Primary returns a copy of the primary key
+func (k *Keyring) Primary() (Key, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if k.primary == "" {
+		return Key{}, ErrNoPrimaryKey
+	}
+	return k.keys[k.primary].clone(), nil
+}
+
+// Get returns a copy of the key with the given id
+func (k *Keyring) Get(id string) (Key, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[id]
+	if !ok {
+		return Key{}, ErrKeyNotFound
+	}
+	return key.clone(), nil
+}
+
+// active returns the key with the given id if it is accepted
+func (k *Keyring) active(id string) (Key, error) {
+	key, err := k.Get(id)
+	if err != nil {
+		return Key{}, err
+	}
+	if !key.Active {
+		return Key{}, fmt.Errorf("crypto: key %q is not active", id)
+	}
+	return key, nil
+}
+
+// IDs returns the IDs of all keys, oldest first
+func (k *Keyring) IDs() []string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	keys := make([]*Key, 0, len(k.keys))
+	for _, key := range k.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].Created.Equal(keys[j].Created) {
+			return keys[i].Created.Before(keys[j].Created)
+		}
+		return keys[i].ID < keys[j].ID
+	})
+	ids := make([]string, len(keys))
+	for i, key := range keys {
+		ids[i] = key.ID
+	}
+	return ids
+}
+
+func (key *Key) clone() Key {
+	c := *key
+	c.Material = append([]byte(nil), key.Material...)
+	return c
+}
+
+// Encrypt encrypts plaintext with the primary key, recording its ID in the
+// envelope
+func (k *Keyring) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	key, err := k.Primary()
+	if err != nil {
+		return nil, err
+	}
+	return Encrypt(key.Material, plaintext, associatedData, EncryptOptions{Algorithm: key.Algorithm, KeyID: key.ID})
+}
+
+// Decrypt decrypts an envelope made with any active key of the keyring
+func (k *Keyring) Decrypt(envelope, associatedData []byte) ([]byte, error) {
+	h, err := ParseEnvelopeHeader(envelope)
+	if err != nil {
+		return nil, err
+	}
+	key, err := k.active(h.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	return Decrypt(key.Material, envelope, associatedData)
+}
+
+// keyringFile is the encrypted form written by Save
+type keyringFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Data       []byte `json:"data"`
+}
+
+// keyringData is the plaintext inside a keyringFile
+type keyringData struct {
+	Primary string `json:"primary"`
+	Keys    []Key  `json:"keys"`
+}
+
+// keyringIterations is the PBKDF2 cost used to protect saved keyrings
+const keyringIterations = 600000
+
+// keyringAD binds the encrypted keyring to its purpose
+var keyringAD = []byte("goutils keyring v1")
+
+// Save writes the keyring to path, encrypted with a key derived from
+// passphrase. The file is only readable by its owner.
+func (k *Keyring) Save(path, passphrase string) error {
+	k.mu.RLock()
+	data := keyringData{Primary: k.primary}
+	for _, id := range k.idsLocked() {
+		data.Keys = append(data.Keys, *k.keys[id])
+	}
+	plaintext, err := json.Marshal(data)
+	k.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	salt, err := RandomBytes(16)
+	if err != nil {
+		return err
+	}
+	kek := PBKDF2(passphrase, salt, keyringIterations, EncryptionKeySize)
+	sealed, err := Encrypt(kek, plaintext, keyringAD, EncryptOptions{})
+	if err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(keyringFile{
+		Version:    1,
+		KDF:        "pbkdf2-sha256",
+		Iterations: keyringIterations,
+		Salt:       salt,
+		Data:       sealed,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0600)
+}
+
+// idsLocked is IDs for callers already holding the lock
+func (k *Keyring) idsLocked() []string {
+	ids := make([]string, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// LoadKeyring reads a keyring written by Save. A wrong passphrase gives
+// ErrDecrypt.
+func LoadKeyring(path, passphrase string) (*Keyring, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file keyringFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("crypto: invalid keyring file: %w", err)
+	}
+	if file.Version != 1 || file.KDF != "pbkdf2-sha256" || file.Iterations < 1 {
+		return nil, fmt.Errorf("crypto: unsupported keyring file version %d", file.Version)
+	}
+
+	kek := PBKDF2(passphrase, file.Salt, file.Iterations, EncryptionKeySize)
+	plaintext, err := Decrypt(kek, file.Data, keyringAD)
+	if err != nil {
+		return nil, err
+	}
+	var data keyringData
+	if err := json.Unmarshal(plaintext, &data); err != nil {
+		return nil, fmt.Errorf("crypto: invalid keyring contents: %w", err)
+	}
+
+	ring := NewKeyring()
+	for i := range data.Keys {
+		key := data.Keys[i]
+		ring.keys[key.ID] = &key
+	}
+	if _, ok := ring.keys[data.Primary]; !ok && len(ring.keys) > 0 {
+		return nil, fmt.Errorf("crypto: keyring primary key %q is missing", data.Primary)
+	}
+	ring.primary = data.Primary
+	return ring, nil
+}
@@ -0,0 +1,113 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"path/filepath"
+	"testing"
+)
+
+func TestHKDF(t *testing.T) {
+	// RFC 5869 test case 1
+	ikm, _ := hex.DecodeString("0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b")
+	salt, _ := hex.DecodeString("000102030405060708090a0b0c")
+	info, _ := hex.DecodeString("f0f1f2f3f4f5f6f7f8f9")
+	expected := "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865"
+
+	key, err := HKDF(ikm, salt, info, 42)
+	if err != nil || hex.EncodeToString(key) != expected {
+		t.Errorf("HKDF = %x, %v; expected %s", key, err, expected)
+	}
+
+	if _, err := HKDF(ikm, nil, nil, 255*32+1); err == nil {
+		t.Error("HKDF should reject lengths beyond 255 hash blocks")
+	}
+}
+
+func TestPBKDF2(t *testing.T) {
+	// RFC 7914 section 11 test vector for PBKDF2-HMAC-SHA256
+	expected := "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"
+	key := PBKDF2("passwd", []byte("salt"), 1, 64)
+	if hex.EncodeToString(key) != expected {
+		t.Errorf("PBKDF2 = %x; expected %s", key, expected)
+	}
+}
+
+func TestKeyringRotation(t *testing.T) {
+	ring := NewKeyring()
+	if _, err := ring.Encrypt([]byte("x"), nil); err != ErrNoPrimaryKey {
+		t.Errorf("Encrypt on an empty keyring: got %v; expected ErrNoPrimaryKey", err)
+	}
+
+	if err := ring.Rotate("k1", AES256GCM); err != nil {
+		t.Fatalf("Rotate(k1) returned error: %v", err)
+	}
+	old, _ := ring.Encrypt([]byte("old secret"), nil)
+
+	if err := ring.Rotate("k2", XChaCha20Poly1305); err != nil {
+		t.Fatalf("Rotate(k2) returned error: %v", err)
+	}
+	current, _ := ring.Encrypt([]byte("new secret"), nil)
+	if h, _ := ParseEnvelopeHeader(current); h.KeyID != "k2" || h.Algorithm != XChaCha20Poly1305 {
+		t.Errorf("Encrypt after rotation used %+v; expected the primary key k2", h)
+	}
+
+	for _, envelope := range [][]byte{old, current} {
+		if _, err := ring.Decrypt(envelope, nil); err != nil {
+			t.Errorf("Decrypt returned error: %v", err)
+		}
+	}
+
+	if err := ring.Deactivate("k2"); err == nil {
+		t.Error("Deactivate should refuse the primary key")
+	}
+	if err := ring.Deactivate("k1"); err != nil {
+		t.Fatalf("Deactivate(k1) returned error: %v", err)
+	}
+	if _, err := ring.Decrypt(old, nil); err == nil {
+		t.Error("Decrypt should reject data under a deactivated key")
+	}
+
+	if ids := ring.IDs(); len(ids) != 2 || ids[0] != "k1" || ids[1] != "k2" {
+		t.Errorf("IDs() = %v; expected [k1 k2]", ids)
+	}
+	if err := ring.Add("k2", make([]byte, 32), 0); err == nil {
+		t.Error("Add should reject a duplicate ID")
+	}
+	if err := ring.Add("short", make([]byte, 16), 0); err == nil {
+		t.Error("Add should reject a key of the wrong size")
+	}
+}
+
+func TestKeyringSaveAndLoad(t *testing.T) {
+	ring := NewKeyring()
+	ring.Rotate("2023", 0)
+	ring.Rotate("2024", 0)
+	ring.Deactivate("2023")
+	envelope, _ := ring.Encrypt([]byte("payload"), nil)
+
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	if err := ring.Save(path, "correct horse battery staple"); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	if _, err := LoadKeyring(path, "wrong passphrase"); err != ErrDecrypt {
+		t.Errorf("LoadKeyring with the wrong passphrase: got %v; expected ErrDecrypt", err)
+	}
+
+	loaded, err := LoadKeyring(path, "correct horse battery staple")
+	if err != nil {
+		t.Fatalf("LoadKeyring returned error: %v", err)
+	}
+	primary, _ := loaded.Primary()
+	if primary.ID != "2024" {
+		t.Errorf("loaded primary key is %q; expected 2024", primary.ID)
+	}
+	if old, _ := loaded.Get("2023"); old.Active {
+		t.Error("loaded keyring should keep key 2023 inactive")
+	}
+	plaintext, err := loaded.Decrypt(envelope, nil)
+	if err != nil || !bytes.Equal(plaintext, []byte("payload")) {
+		t.Errorf("loaded keyring Decrypt = %q, %v", plaintext, err)
+	}
+}
@@ -0,0 +1,540 @@
+package crypto
+
+import (
+	"bytes"
+	stdcrypto "crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"os"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// KeyType selects the kind of key pair GenerateKey creates
+type KeyType int
+
+// Supported key pair types
+const (
+	RSA2048 KeyType = iota
+	RSA3072
+	RSA4096
+	ECDSAP256
+	ECDSAP384
+	Ed25519
+)
+
+// KeyFormat is the encoding of a key in a PEM block
+type KeyFormat int
+
+const (
+	// PKCS8 is the "PRIVATE KEY" format for private keys of any type
+	PKCS8 KeyFormat = iota
+	// PKIX is the "PUBLIC KEY" format for public keys of any type
+	PKIX
+	// PKCS1 is the "RSA PRIVATE KEY" or "RSA PUBLIC KEY" format
+	PKCS1
+	// SEC1 is the "EC PRIVATE KEY" format
+	SEC1
+)
+
+var (
+	// ErrInvalidSignature is returned by a Verifier for a signature that
+	// does not match the data
+	ErrInvalidSignature = errors.New("crypto: invalid signature")
+	// ErrIncorrectPassphrase is returned when an encrypted private key
+	// cannot be decrypted with the given passphrase
+	ErrIncorrectPassphrase = errors.New("crypto: incorrect passphrase")
+)
+
+// Signer signs data with a private key. RSA keys use PKCS #1 v1.5 with
+// SHA-256, ECDSA keys use ASN.1 signatures with SHA-256 on P-256 and
+// SHA-384 on P-384, and Ed25519 signs the data directly. These are the
+// choices of "openssl dgst -sign" for the same keys.
+type Signer interface {
+	Sign(data []byte) ([]byte, error)
+	// Verifier returns the Verifier for the matching public key
+	Verifier() Verifier
+	// PrivateKey returns the underlying key for use with the standard library
+	PrivateKey() stdcrypto.Signer
+}
+
+// Verifier checks signatures made by the matching Signer
+type Verifier interface {
+	// Verify returns ErrInvalidSignature if signature does not match data
+	Verify(data, signature []byte) error
+	// PublicKey returns the underlying key for use with the standard library
+	PublicKey() stdcrypto.PublicKey
+}
+
+// GenerateKey creates a new key pair of the given type
+func GenerateKey(kt KeyType) (Signer, error) {
+	var key stdcrypto.Signer
+	var err error
+	switch kt {
+	case RSA2048, RSA3072, RSA4096:
+		key, err = rsa.GenerateKey(rand.Reader, map[KeyType]int{RSA2048: 2048, RSA3072: 3072, RSA4096: 4096}[kt])
+	case ECDSAP256:
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case ECDSAP384:
+		key, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case Ed25519:
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("crypto: unsupported key type %d", kt)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewSigner(key)
+}
+
+// NewSigner wraps an *rsa.PrivateKey, *ecdsa.PrivateKey or
+// ed25519.PrivateKey
+func NewSigner(key stdcrypto.Signer) (Signer, error) {
+	if _, err := NewVerifier(key.Public()); err != nil {
+		return nil, err
+	}
+	return &keySigner{key: key}, nil
+}
+
+// NewVerifier wraps an *rsa.PublicKey, *ecdsa.PublicKey or
+// ed25519.PublicKey
+func NewVerifier(key stdcrypto.PublicKey) (Verifier, error) {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() && k.Curve != elliptic.P384() {
+			return nil, fmt.Errorf("crypto: unsupported curve %s", k.Curve.Params().Name)
+		}
+	case ed25519.PublicKey:
+	default:
+		return nil, fmt.Errorf("crypto: unsupported public key type %T", key)
+	}
+	return &keyVerifier{key: key}, nil
+}
+
+type keySigner struct {
+	key stdcrypto.Signer
+}
+
+type keyVerifier struct {
+	key stdcrypto.PublicKey
+}
+
+// signatureHash returns the digest algorithm used with a key, or 0 for
+// Ed25519, which hashes internally
+func signatureHash(key stdcrypto.PublicKey) stdcrypto.Hash {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return stdcrypto.SHA256
+	case *ecdsa.PublicKey:
+		if k.Curve == elliptic.P384() {
+			return stdcrypto.SHA384
+		}
+		return stdcrypto.SHA256
+	}
+	return 0
+}
+
+func digest(h stdcrypto.Hash, data []byte) []byte {
+	if h == 0 {
+		return data
+	}
+	d := h.New()
+	d.Write(data)
+	return d.Sum(nil)
+}
+
+func (s *keySigner) Sign(data []byte) ([]byte, error) {
+	h := signatureHash(s.key.Public())
+	return s.key.Sign(rand.Reader, digest(h, data), h)
+}
+
+func (s *keySigner) Verifier() Verifier {
+	return &keyVerifier{key: s.key.Public()}
+}
+
+func (s *keySigner) PrivateKey() stdcrypto.Signer {
+	return s.key
+}
+
+func (v *keyVerifier) Verify(data, signature []byte) error {
+	h := signatureHash(v.key)
+	ok := false
+	switch k := v.key.(type) {
+	case *rsa.PublicKey:
+		ok = rsa.VerifyPKCS1v15(k, h, digest(h, data), signature) == nil
+	case *ecdsa.PublicKey:
+		ok = ecdsa.VerifyASN1(k, digest(h, data), signature)
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(k, data, signature)
+	}
+	if !ok {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (v *keyVerifier) PublicKey() stdcrypto.PublicKey {
+	return v.key
+}
+
+// MarshalPrivateKeyPEM encodes a private key as PEM. With a passphrase,
+// PKCS8 keys are written as "ENCRYPTED PRIVATE KEY" using PBES2 with
+// PBKDF2-SHA256 and AES-256-CBC, which OpenSSL reads. PKCS1 and SEC1 keys
+// use the legacy encrypted PEM headers instead, whose key derivation is
+// weak; prefer PKCS8 when the reader supports it.
+func MarshalPrivateKeyPEM(key stdcrypto.PrivateKey, format KeyFormat, passphrase string) ([]byte, error) {
+	var block *pem.Block
+	switch format {
+	case PKCS8:
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if passphrase != "" {
+			if der, err = encryptPKCS8(der, passphrase); err != nil {
+				return nil, err
+			}
+			return pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: der}), nil
+		}
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	case PKCS1:
+		k, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("crypto: PKCS1 needs an RSA key, got %T", key)
+		}
+		block = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)}
+	case SEC1:
+		k, ok := key.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("crypto: SEC1 needs an ECDSA key, got %T", key)
+		}
+		der, err := x509.MarshalECPrivateKey(k)
+		if err != nil {
+			return nil, err
+		}
+		block = &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}
+	default:
+		return nil, fmt.Errorf("crypto: format %d cannot hold a private key", format)
+	}
+
+	if passphrase != "" {
+		encrypted, err := x509.EncryptPEMBlock(rand.Reader, block.Type, block.Bytes, []byte(passphrase), x509.PEMCipherAES256)
+		if err != nil {
+			return nil, err
+		}
+		block = encrypted
+	}
+	return pem.EncodeToMemory(block), nil
+}
+
+// MarshalPublicKeyPEM encodes a public key as PEM in PKIX or, for RSA
+// keys, PKCS1 format
+func MarshalPublicKeyPEM(key stdcrypto.PublicKey, format KeyFormat) ([]byte, error) {
+	switch format {
+	case PKIX:
+		der, err := x509.MarshalPKIXPublicKey(key)
+		if err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+	case PKCS1:
+		k, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("crypto: PKCS1 needs an RSA key, got %T", key)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(k)}), nil
+	}
+	return nil, fmt.Errorf("crypto: format %d cannot hold a public key", format)
+}
+
+// ParsePrivateKeyPEM decodes the first private key in data, detecting its
+// format. passphrase is only used for encrypted keys.
+func ParsePrivateKeyPEM(data []byte, passphrase string) (stdcrypto.Signer, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("crypto: no private key found in PEM data")
+		}
+
+		// Legacy "Proc-Type: 4,ENCRYPTED" blocks are still written by
+		// older tools, so they are read despite their weak key derivation
+		der := block.Bytes
+		if x509.IsEncryptedPEMBlock(block) {
+			if passphrase == "" {
+				return nil, ErrIncorrectPassphrase
+			}
+			var err error
+			if der, err = x509.DecryptPEMBlock(block, []byte(passphrase)); err != nil {
+				return nil, ErrIncorrectPassphrase
+			}
+		}
+
+		var key interface{}
+		var err error
+		switch block.Type {
+		case "PRIVATE KEY":
+			key, err = x509.ParsePKCS8PrivateKey(der)
+		case "ENCRYPTED PRIVATE KEY":
+			if passphrase == "" {
+				return nil, ErrIncorrectPassphrase
+			}
+			if der, err = decryptPKCS8(der, passphrase); err != nil {
+				return nil, err
+			}
+			if key, err = x509.ParsePKCS8PrivateKey(der); err != nil {
+				return nil, ErrIncorrectPassphrase
+			}
+		case "RSA PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(der)
+		case "EC PRIVATE KEY":
+			key, err = x509.ParseECPrivateKey(der)
+		default:
+			// Skip other blocks such as EC PARAMETERS or certificates
+			continue
+		}
+		if err != nil {
+			if x509.IsEncryptedPEMBlock(block) {
+				return nil, ErrIncorrectPassphrase
+			}
+			return nil, fmt.Errorf("crypto: invalid %s: %w", block.Type, err)
+		}
+		signer, ok := key.(stdcrypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("crypto: unsupported private key type %T", key)
+		}
+		return signer, nil
+	}
+}
+
+// ParsePublicKeyPEM decodes the first public key in data. It accepts PKIX
+// and PKCS1 public keys as well as certificates, whose public key is
+// returned.
+func ParsePublicKeyPEM(data []byte) (stdcrypto.PublicKey, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("crypto: no public key found in PEM data")
+		}
+		switch block.Type {
+		case "PUBLIC KEY":
+			return x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			return x509.ParsePKCS1PublicKey(block.Bytes)
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			return cert.PublicKey, nil
+		}
+	}
+}
+
+// LoadSigner reads a PEM private key file, as written by SavePrivateKey
+// or OpenSSL
+func LoadSigner(path, passphrase string) (Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ParsePrivateKeyPEM(data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return NewSigner(key)
+}
+
+// LoadVerifier reads a PEM public key or certificate file
+func LoadVerifier(path string) (Verifier, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ParsePublicKeyPEM(data)
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifier(key)
+}
+
+// SavePrivateKey writes the signer's key to path as PKCS8 PEM, encrypted
+// if passphrase is not empty. The file is only readable by its owner.
+func SavePrivateKey(path string, s Signer, passphrase string) error {
+	data, err := MarshalPrivateKeyPEM(s.PrivateKey(), PKCS8, passphrase)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// SavePublicKey writes the verifier's key to path as PKIX PEM
+func SavePublicKey(path string, v Verifier) error {
+	data, err := MarshalPublicKeyPEM(v.PublicKey(), PKIX)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Object identifiers of PKCS #5 v2 (RFC 8018) password-based encryption
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+	oidAES128CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+// pkcs8Iterations is the PBKDF2 cost of keys encrypted by
+// MarshalPrivateKeyPEM
+const pkcs8Iterations = 600000
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	Data      []byte
+}
+
+type pbes2Params struct {
+	KeyDerivation pkix.AlgorithmIdentifier
+	Encryption    pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt       []byte
+	Iterations int
+	KeyLength  int                      `asn1:"optional"`
+	PRF        pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+func encryptPKCS8(der []byte, passphrase string) ([]byte, error) {
+	salt, err := RandomBytes(16)
+	if err != nil {
+		return nil, err
+	}
+	iv, err := RandomBytes(aes.BlockSize)
+	if err != nil {
+		return nil, err
+	}
+	key := pbkdf2.Key([]byte(passphrase), salt, pkcs8Iterations, 32, sha256.New)
+
+	// PKCS #7 padding, then AES-256-CBC
+	padding := aes.BlockSize - len(der)%aes.BlockSize
+	plaintext := append(append([]byte(nil), der...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	block, _ := aes.NewCipher(key)
+	ciphertext := make([]byte, len(plaintext))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, plaintext)
+
+	kdfParams, err := asn1.Marshal(pbkdf2Params{
+		Salt:       salt,
+		Iterations: pkcs8Iterations,
+		PRF:        pkix.AlgorithmIdentifier{Algorithm: oidHMACWithSHA256, Parameters: asn1.NullRawValue},
+	})
+	if err != nil {
+		return nil, err
+	}
+	ivParam, err := asn1.Marshal(iv)
+	if err != nil {
+		return nil, err
+	}
+	params, err := asn1.Marshal(pbes2Params{
+		KeyDerivation: pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdfParams}},
+		Encryption:    pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: ivParam}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(encryptedPrivateKeyInfo{
+		Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: params}},
+		Data:      ciphertext,
+	})
+}
+
+func decryptPKCS8(der []byte, passphrase string) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("crypto: invalid encrypted private key: %w", err)
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("crypto: unsupported private key encryption %v", info.Algorithm.Algorithm)
+	}
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, fmt.Errorf("crypto: invalid PBES2 parameters: %w", err)
+	}
+	if !params.KeyDerivation.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("crypto: unsupported key derivation %v", params.KeyDerivation.Algorithm)
+	}
+	var kdf pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivation.Parameters.FullBytes, &kdf); err != nil {
+		return nil, fmt.Errorf("crypto: invalid PBKDF2 parameters: %w", err)
+	}
+
+	var prf func() hash.Hash
+	switch {
+	case len(kdf.PRF.Algorithm) == 0, kdf.PRF.Algorithm.Equal(oidHMACWithSHA1):
+		prf = sha1.New
+	case kdf.PRF.Algorithm.Equal(oidHMACWithSHA256):
+		prf = sha256.New
+	case kdf.PRF.Algorithm.Equal(oidHMACWithSHA512):
+		prf = sha512.New
+	default:
+		return nil, fmt.Errorf("crypto: unsupported PBKDF2 hash %v", kdf.PRF.Algorithm)
+	}
+
+	var keyLen int
+	switch {
+	case params.Encryption.Algorithm.Equal(oidAES128CBC):
+		keyLen = 16
+	case params.Encryption.Algorithm.Equal(oidAES192CBC):
+		keyLen = 24
+	case params.Encryption.Algorithm.Equal(oidAES256CBC):
+		keyLen = 32
+	default:
+		return nil, fmt.Errorf("crypto: unsupported private key cipher %v", params.Encryption.Algorithm)
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.Encryption.Parameters.FullBytes, &iv); err != nil || len(iv) != aes.BlockSize {
+		return nil, errors.New("crypto: invalid private key cipher IV")
+	}
+	if kdf.Iterations < 1 || len(info.Data) == 0 || len(info.Data)%aes.BlockSize != 0 {
+		return nil, errors.New("crypto: invalid encrypted private key")
+	}
+
+	key := pbkdf2.Key([]byte(passphrase), kdf.Salt, kdf.Iterations, keyLen, prf)
+	block, _ := aes.NewCipher(key)
+	plaintext := make([]byte, len(info.Data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, info.Data)
+
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, ErrIncorrectPassphrase
+	}
+	for _, b := range plaintext[len(plaintext)-padding:] {
+		if int(b) != padding {
+			return nil, ErrIncorrectPassphrase
+		}
+	}
+	return plaintext[:len(plaintext)-padding], nil
+}
@@ -0,0 +1,247 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OTPSecretSize is the size in bytes of secrets from GenerateOTPSecret,
+// the 160 bits recommended by RFC 4226
+const OTPSecretSize = 20
+
+var (
+	// ErrInvalidOTP is returned when a one-time password does not match
+	ErrInvalidOTP = errors.New("crypto: invalid one-time password")
+	// ErrOTPReused is returned when a matching one-time password is
+	// rejected by the replay check
+	ErrOTPReused = errors.New("crypto: one-time password already used")
+)
+
+// OTPOptions configures HOTP and TOTP. The zero value gives the defaults
+// understood by every authenticator app: SHA1, 6 digits and 30 seconds.
+type OTPOptions struct {
+	// Algorithm is SHA1, SHA256 or SHA512
+	Algorithm HashAlgorithm
+	// Digits is the code length, from 6 to 10
+	Digits int
+	// Period is the TOTP time step, in whole seconds
+	Period time.Duration
+	// Skew is the number of steps either side of the current time that
+	// TOTP verification accepts, or the number of counters ahead of the
+	// expected one that HOTP verification accepts
+	Skew int
+	// ReplayCheck, if set, is called with the counter of a matching code
+	// before it is accepted. It should record the counter and return false
+	// if the code must be rejected, typically because the counter is not
+	// above the last one accepted for the same secret.
+	ReplayCheck func(counter uint64) bool
+}
+
+// withDefaults fills in defaults and validates the options
+func (o OTPOptions) withDefaults() (OTPOptions, error) {
+	if o.Algorithm == "" {
+		o.Algorithm = SHA1
+	}
+	if o.Digits == 0 {
+		o.Digits = 6
+	}
+	if o.Period == 0 {
+		o.Period = 30 * time.Second
+	}
+	switch o.Algorithm {
+	case SHA1, SHA256, SHA512:
+	default:
+		return o, fmt.Errorf("crypto: unsupported OTP algorithm %q", o.Algorithm)
+	}
+	if o.Digits < 6 || o.Digits > 10 {
+		return o, fmt.Errorf("crypto: OTP digits must be 6 to 10, got %d", o.Digits)
+	}
+	if o.Period < time.Second || o.Period%time.Second != 0 {
+		return o, fmt.Errorf("crypto: OTP period must be whole seconds, got %v", o.Period)
+	}
+	if o.Skew < 0 {
+		return o, fmt.Errorf("crypto: OTP skew must not be negative")
+	}
+	return o, nil
+}
+
+// GenerateOTPSecret returns a new random secret for HOTP or TOTP in
+// unpadded base32, the form authenticator apps expect
+func GenerateOTPSecret() (string, error) {
+	secret, err := RandomBytes(OTPSecretSize)
+	if err != nil {
+		return "", err
+	}
+	return Base32Raw.EncodeToString(secret), nil
+}
+
+// decodeOTPSecret decodes a base32 secret, ignoring case, spaces and
+// padding as people tend to copy them
+func decodeOTPSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.NewReplacer(" ", "", "-", "", "=", "").Replace(secret))
+	key, err := Base32Raw.DecodeString(secret)
+	if err != nil || len(key) == 0 {
+		return nil, fmt.Errorf("crypto: invalid OTP secret: %w", ErrInvalidEncoding)
+	}
+	return key, nil
+}
+
+// HOTP returns the RFC 4226 one-time password for counter
+func HOTP(secret string, counter uint64, opts OTPOptions) (string, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return "", err
+	}
+	key, err := decodeOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return hotp(key, counter, opts), nil
+}
+
+// TOTP returns the RFC 6238 one-time password for time t
+func TOTP(secret string, t time.Time, opts OTPOptions) (string, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return "", err
+	}
+	return HOTP(secret, totpCounter(t, opts.Period), opts)
+}
+
+// totpCounter returns the number of periods between the Unix epoch and t
+func totpCounter(t time.Time, period time.Duration) uint64 {
+	return uint64(t.Unix()) / uint64(period/time.Second)
+}
+
+// hotp computes a code with validated options
+func hotp(key []byte, counter uint64, opts OTPOptions) string {
+	newHash := func() hash.Hash {
+		h, _ := NewHash(opts.Algorithm)
+		return h
+	}
+	mac := hmac.New(newHash, key)
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	code := uint64(binary.BigEndian.Uint32(sum[offset:]) & 0x7fffffff)
+	mod := uint64(1)
+	for i := 0; i < opts.Digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", opts.Digits, code%mod)
+}
+
+// VerifyHOTP checks code against counter and the opts.Skew counters after
+// it, and returns the matching counter. The next code expected is the one
+// for the returned counter plus one.
+func VerifyHOTP(code, secret string, counter uint64, opts OTPOptions) (uint64, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return 0, err
+	}
+	key, err := decodeOTPSecret(secret)
+	if err != nil {
+		return 0, err
+	}
+	return verifyOTP(code, key, counter, counter+uint64(opts.Skew), opts)
+}
+
+// VerifyTOTP checks code against the time step of t and opts.Skew steps
+// either side of it
+func VerifyTOTP(code, secret string, t time.Time, opts OTPOptions) error {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return err
+	}
+	key, err := decodeOTPSecret(secret)
+	if err != nil {
+		return err
+	}
+	current := totpCounter(t, opts.Period)
+	first := uint64(0)
+	if current > uint64(opts.Skew) {
+		first = current - uint64(opts.Skew)
+	}
+	_, err = verifyOTP(code, key, first, current+uint64(opts.Skew), opts)
+	return err
+}
+
+// verifyOTP compares code with the codes of every counter from first to
+// last in constant time, then applies the replay check to the match
+func verifyOTP(code string, key []byte, first, last uint64, opts OTPOptions) (uint64, error) {
+	matched, found := uint64(0), false
+	for counter := first; ; counter++ {
+		if subtle.ConstantTimeCompare([]byte(code), []byte(hotp(key, counter, opts))) == 1 && !found {
+			matched, found = counter, true
+		}
+		if counter == last {
+			break
+		}
+	}
+	if !found {
+		return 0, ErrInvalidOTP
+	}
+	if opts.ReplayCheck != nil && !opts.ReplayCheck(matched) {
+		return 0, ErrOTPReused
+	}
+	return matched, nil
+}
+
+// TOTPURI returns the otpauth:// provisioning URI for a TOTP secret, to be
+// shown as a QR code. The issuer names the service and account the user.
+func TOTPURI(issuer, account, secret string, opts OTPOptions) (string, error) {
+	return otpURI("totp", issuer, account, secret, nil, opts)
+}
+
+// HOTPURI returns the otpauth:// provisioning URI for an HOTP secret
+// starting at counter
+func HOTPURI(issuer, account, secret string, counter uint64, opts OTPOptions) (string, error) {
+	return otpURI("hotp", issuer, account, secret, &counter, opts)
+}
+
+// otpURI builds the key URI format understood by authenticator apps
+func otpURI(kind, issuer, account, secret string, counter *uint64, opts OTPOptions) (string, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return "", err
+	}
+	key, err := decodeOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	if account == "" {
+		return "", errors.New("crypto: OTP account name is required")
+	}
+
+	// Spaces are escaped as %20 rather than "+", which some apps show as is
+	escape := func(s string) string {
+		return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+	}
+	label := escape(account)
+	params := []string{"secret=" + Base32Raw.EncodeToString(key)}
+	if issuer != "" {
+		label = escape(issuer) + ":" + label
+		params = append(params, "issuer="+escape(issuer))
+	}
+	params = append(params,
+		"algorithm="+strings.ToUpper(string(opts.Algorithm)),
+		"digits="+strconv.Itoa(opts.Digits))
+	if counter != nil {
+		params = append(params, "counter="+strconv.FormatUint(*counter, 10))
+	} else {
+		params = append(params, "period="+strconv.Itoa(int(opts.Period/time.Second)))
+	}
+	return "otpauth://" + kind + "/" + label + "?" + strings.Join(params, "&"), nil
+}
@@ -0,0 +1,173 @@
+package crypto
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHOTPSpecVectors(t *testing.T) {
+	// RFC 4226 appendix D, secret "12345678901234567890"
+	secret := Base32Raw.EncodeToString([]byte("12345678901234567890"))
+	expected := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+
+	for counter, want := range expected {
+		code, err := HOTP(secret, uint64(counter), OTPOptions{})
+		if err != nil {
+			t.Fatalf("HOTP returned error: %v", err)
+		}
+		if code != want {
+			t.Errorf("HOTP(counter %d) = %s; expected %s", counter, code, want)
+		}
+	}
+}
+
+func TestTOTPSpecVectors(t *testing.T) {
+	// RFC 6238 appendix B, 8 digits, with a key of the hash size for each
+	secrets := map[HashAlgorithm]string{
+		SHA1:   Base32Raw.EncodeToString([]byte("12345678901234567890")),
+		SHA256: Base32Raw.EncodeToString([]byte("12345678901234567890123456789012")),
+		SHA512: Base32Raw.EncodeToString([]byte("1234567890123456789012345678901234567890123456789012345678901234")),
+	}
+	tests := []struct {
+		unix   int64
+		alg    HashAlgorithm
+		expect string
+	}{
+		{59, SHA1, "94287082"},
+		{59, SHA256, "46119246"},
+		{59, SHA512, "90693936"},
+		{1111111109, SHA1, "07081804"},
+		{1111111109, SHA256, "68084774"},
+		{1111111109, SHA512, "25091201"},
+		{1234567890, SHA1, "89005924"},
+		{1234567890, SHA256, "91819424"},
+		{1234567890, SHA512, "93441116"},
+		{20000000000, SHA1, "65353130"},
+		{20000000000, SHA256, "77737706"},
+		{20000000000, SHA512, "47863826"},
+	}
+
+	for _, test := range tests {
+		opts := OTPOptions{Algorithm: test.alg, Digits: 8}
+		code, err := TOTP(secrets[test.alg], time.Unix(test.unix, 0), opts)
+		if err != nil {
+			t.Fatalf("TOTP returned error: %v", err)
+		}
+		if code != test.expect {
+			t.Errorf("TOTP(%d, %s) = %s; expected %s", test.unix, test.alg, code, test.expect)
+		}
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	secret, err := GenerateOTPSecret()
+	if err != nil {
+		t.Fatalf("GenerateOTPSecret returned error: %v", err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("GenerateOTPSecret = %q; expected 32 base32 characters", secret)
+	}
+
+	now := time.Unix(1700000000, 0)
+	code, _ := TOTP(secret, now.Add(-30*time.Second), OTPOptions{})
+
+	if err := VerifyTOTP(code, secret, now, OTPOptions{}); !errors.Is(err, ErrInvalidOTP) {
+		t.Errorf("VerifyTOTP of the previous code without skew: got %v; expected ErrInvalidOTP", err)
+	}
+	if err := VerifyTOTP(code, secret, now, OTPOptions{Skew: 1}); err != nil {
+		t.Errorf("VerifyTOTP of the previous code with skew 1 returned error: %v", err)
+	}
+	if err := VerifyTOTP(code, secret, now.Add(time.Minute), OTPOptions{Skew: 1}); !errors.Is(err, ErrInvalidOTP) {
+		t.Errorf("VerifyTOTP outside the skew window: got %v; expected ErrInvalidOTP", err)
+	}
+
+	// Secrets are accepted the way people copy them
+	sloppy := strings.ToLower(secret[:4]) + " " + secret[4:] + "===="
+	if err := VerifyTOTP(code, sloppy, now, OTPOptions{Skew: 1}); err != nil {
+		t.Errorf("VerifyTOTP with a lower case spaced secret returned error: %v", err)
+	}
+}
+
+func TestOTPReplayCheck(t *testing.T) {
+	secret, _ := GenerateOTPSecret()
+	now := time.Unix(1700000000, 0)
+	code, _ := TOTP(secret, now, OTPOptions{})
+
+	// Accept only counters above the last accepted one
+	var last uint64
+	opts := OTPOptions{Skew: 1, ReplayCheck: func(counter uint64) bool {
+		if counter <= last {
+			return false
+		}
+		last = counter
+		return true
+	}}
+
+	if err := VerifyTOTP(code, secret, now, opts); err != nil {
+		t.Fatalf("first VerifyTOTP returned error: %v", err)
+	}
+	if err := VerifyTOTP(code, secret, now.Add(10*time.Second), opts); !errors.Is(err, ErrOTPReused) {
+		t.Errorf("second VerifyTOTP of the same code: got %v; expected ErrOTPReused", err)
+	}
+}
+
+func TestVerifyHOTP(t *testing.T) {
+	secret := Base32Raw.EncodeToString([]byte("12345678901234567890"))
+
+	// The user pressed the button twice without logging in
+	counter, err := VerifyHOTP("969429", secret, 1, OTPOptions{Skew: 3})
+	if err != nil || counter != 3 {
+		t.Errorf("VerifyHOTP look-ahead = %d, %v; expected counter 3", counter, err)
+	}
+	if _, err := VerifyHOTP("969429", secret, 4, OTPOptions{Skew: 3}); !errors.Is(err, ErrInvalidOTP) {
+		t.Errorf("VerifyHOTP of a past counter: got %v; expected ErrInvalidOTP", err)
+	}
+}
+
+func TestOTPOptionErrors(t *testing.T) {
+	secret, _ := GenerateOTPSecret()
+	invalid := []OTPOptions{
+		{Algorithm: MD5},
+		{Digits: 5},
+		{Digits: 11},
+		{Period: 1500 * time.Millisecond},
+		{Skew: -1},
+	}
+	for _, opts := range invalid {
+		if _, err := TOTP(secret, time.Now(), opts); err == nil {
+			t.Errorf("TOTP with options %+v should fail", opts)
+		}
+	}
+	if _, err := HOTP("not base32!", 0, OTPOptions{}); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("HOTP with an invalid secret: got %v; expected ErrInvalidEncoding", err)
+	}
+}
+
+func TestOTPURI(t *testing.T) {
+	secret := "JBSWY3DPEHPK3PXP"
+	uri, err := TOTPURI("Acme Admin", "alice@example.com", secret, OTPOptions{Algorithm: SHA256, Digits: 8})
+	if err != nil {
+		t.Fatalf("TOTPURI returned error: %v", err)
+	}
+	expected := "otpauth://totp/Acme%20Admin:alice%40example.com?secret=JBSWY3DPEHPK3PXP&issuer=Acme%20Admin&algorithm=SHA256&digits=8&period=30"
+	if uri != expected {
+		t.Errorf("TOTPURI = %s; expected %s", uri, expected)
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Query().Get("issuer") != "Acme Admin" {
+		t.Errorf("TOTPURI is not a parseable URL: %v", err)
+	}
+
+	uri, _ = HOTPURI("", "bob", secret, 7, OTPOptions{})
+	if uri != "otpauth://hotp/bob?secret=JBSWY3DPEHPK3PXP&algorithm=SHA1&digits=6&counter=7" {
+		t.Errorf("HOTPURI = %s", uri)
+	}
+
+	if _, err := TOTPURI("Acme", "", secret, OTPOptions{}); err == nil {
+		t.Error("TOTPURI should require an account name")
+	}
+}
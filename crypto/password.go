@@ -0,0 +1,340 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// PasswordAlgorithm identifies a password hashing function. The value is
+// the identifier used in the PHC string format.
+type PasswordAlgorithm string
+
+// Supported password hashing functions
+const (
+	Argon2id     PasswordAlgorithm = "argon2id"
+	Scrypt       PasswordAlgorithm = "scrypt"
+	Bcrypt       PasswordAlgorithm = "bcrypt"
+	PBKDF2SHA256 PasswordAlgorithm = "pbkdf2-sha256"
+)
+
+// Argon2Params are the cost parameters of Argon2id
+type Argon2Params struct {
+	// Memory is the memory cost in KiB
+	Memory uint32
+	// Time is the number of passes over the memory
+	Time uint32
+	// Threads is the degree of parallelism
+	Threads uint8
+}
+
+// ScryptParams are the cost parameters of scrypt. N must be a power of two.
+type ScryptParams struct {
+	N int
+	R int
+	P int
+}
+
+// PasswordPolicy selects the algorithm and cost used to hash new passwords.
+// Only the parameters of the chosen algorithm are used.
+type PasswordPolicy struct {
+	Algorithm        PasswordAlgorithm
+	Argon2           Argon2Params
+	Scrypt           ScryptParams
+	BcryptCost       int
+	PBKDF2Iterations int
+	// SaltLength and KeyLength are in bytes. They do not apply to bcrypt,
+	// which fixes both.
+	SaltLength int
+	KeyLength  int
+	// AcceptLegacySHA256 lets VerifyPasswordWith accept the unsalted
+	// SHA-256 hex digests of earlier versions, so that existing users can
+	// log in once more and be rehashed. It is off by default.
+	AcceptLegacySHA256 bool
+}
+
+// DefaultPasswordPolicy follows the OWASP password storage recommendations:
+// Argon2id with 64 MiB of memory, 3 passes and 4 threads
+var DefaultPasswordPolicy = PasswordPolicy{
+	Algorithm:        Argon2id,
+	Argon2:           Argon2Params{Memory: 64 * 1024, Time: 3, Threads: 4},
+	Scrypt:           ScryptParams{N: 1 << 15, R: 8, P: 1},
+	BcryptCost:       12,
+	PBKDF2Iterations: 600000,
+	SaltLength:       16,
+	KeyLength:        32,
+}
+
+// Upper bounds on cost parameters, well above any sensible policy, so that
+// a corrupted or hostile stored hash cannot make VerifyPassword allocate
+// gigabytes or run for hours
+const (
+	maxArgon2Memory     = 1 << 20 // KiB, 1 GiB
+	maxArgon2Time       = 64
+	maxArgon2Threads    = 64
+	maxScryptMemory     = 1 << 30 // bytes, 128*N*r
+	maxPBKDF2Iterations = 10000000
+)
+
+// ErrInvalidHash is returned when a stored password hash cannot be parsed
+var ErrInvalidHash = errors.New("crypto: invalid password hash")
+
+// phcEncoding is the unpadded standard base64 of the PHC string format
+var phcEncoding = base64.RawStdEncoding
+
+// HashPassword hashes password with a random salt using
+// DefaultPasswordPolicy. The result is a self-describing PHC string such as
+// "$argon2id$v=19$m=65536,t=3,p=4$<salt>$<hash>".
+func HashPassword(password string) (string, error) {
+	return HashPasswordWith(password, DefaultPasswordPolicy)
+}
+
+// HashPasswordWith hashes password with a random salt as policy describes
+func HashPasswordWith(password string, policy PasswordPolicy) (string, error) {
+	if policy.Algorithm == Bcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), policy.BcryptCost)
+		if err != nil {
+			return "", fmt.Errorf("crypto: bcrypt: %w", err)
+		}
+		return string(hash), nil
+	}
+
+	if policy.SaltLength < 8 || policy.KeyLength < 16 {
+		return "", fmt.Errorf("crypto: salt must be at least 8 bytes and key at least 16 bytes")
+	}
+	salt, err := RandomBytes(policy.SaltLength)
+	if err != nil {
+		return "", err
+	}
+
+	h := passwordHash{algorithm: policy.Algorithm, salt: salt}
+	switch policy.Algorithm {
+	case Argon2id:
+		h.argon2 = policy.Argon2
+	case Scrypt:
+		h.scrypt = policy.Scrypt
+	case PBKDF2SHA256:
+		h.iterations = policy.PBKDF2Iterations
+	default:
+		return "", fmt.Errorf("crypto: unsupported password algorithm %q", policy.Algorithm)
+	}
+	if err := h.validate(); err != nil {
+		return "", err
+	}
+
+	h.key, err = h.derive(password, policy.KeyLength)
+	if err != nil {
+		return "", err
+	}
+	return h.String(), nil
+}
+
+// VerifyPassword reports whether password matches hash, comparing in
+// constant time. It accepts every format HashPasswordWith produces.
+func VerifyPassword(password, hash string) bool {
+	return VerifyPasswordWith(password, hash, DefaultPasswordPolicy)
+}
+
+// VerifyPasswordWith verifies password as VerifyPassword does, and also
+// accepts legacy SHA-256 hashes if policy.AcceptLegacySHA256 is set. Only
+// enable that while migrating users, replacing each legacy hash after
+// NeedsRehash.
+func VerifyPasswordWith(password, hash string, policy PasswordPolicy) bool {
+	if policy.AcceptLegacySHA256 && isLegacyPasswordHash(hash) {
+		sum := sha256.Sum256([]byte(password + "salt"))
+		return subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(strings.ToLower(hash))) == 1
+	}
+
+	h, err := parsePasswordHash(hash)
+	if err != nil {
+		return false
+	}
+	if h.algorithm == Bcrypt {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+	}
+	key, err := h.derive(password, len(h.key))
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(key, h.key) == 1
+}
+
+// NeedsRehash reports whether hash was made with a different algorithm or
+// different parameters than policy asks for, so that it should be replaced
+// by a new hash the next time the password is known. Unparseable and legacy
+// hashes always need rehashing.
+func NeedsRehash(hash string, policy PasswordPolicy) bool {
+	h, err := parsePasswordHash(hash)
+	if err != nil || h.algorithm != policy.Algorithm {
+		return true
+	}
+	switch h.algorithm {
+	case Argon2id:
+		if h.argon2 != policy.Argon2 {
+			return true
+		}
+	case Scrypt:
+		if h.scrypt != policy.Scrypt {
+			return true
+		}
+	case Bcrypt:
+		cost, err := bcrypt.Cost([]byte(hash))
+		return err != nil || cost != policy.BcryptCost
+	case PBKDF2SHA256:
+		if h.iterations != policy.PBKDF2Iterations {
+			return true
+		}
+	}
+	return len(h.salt) != policy.SaltLength || len(h.key) != policy.KeyLength
+}
+
+// isLegacyPasswordHash recognizes the hex SHA-256 digests that HashPassword
+// produced before it used a password hashing function
+func isLegacyPasswordHash(hash string) bool {
+	if len(hash) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(hash)
+	return err == nil
+}
+
+// passwordHash is a decoded password hash
+type passwordHash struct {
+	algorithm  PasswordAlgorithm
+	argon2     Argon2Params
+	scrypt     ScryptParams
+	iterations int
+	salt       []byte
+	key        []byte
+}
+
+// validate rejects parameters the key derivation functions cannot use or
+// that exceed the cost bounds
+func (h passwordHash) validate() error {
+	switch h.algorithm {
+	case Argon2id:
+		a := h.argon2
+		if a.Memory < 8*uint32(a.Threads) || a.Time < 1 || a.Threads < 1 ||
+			a.Memory > maxArgon2Memory || a.Time > maxArgon2Time || a.Threads > maxArgon2Threads {
+			return fmt.Errorf("crypto: invalid argon2id parameters %+v", a)
+		}
+	case Scrypt:
+		s := h.scrypt
+		if s.N <= 1 || s.N&(s.N-1) != 0 || s.R < 1 || s.P < 1 || uint64(s.R)*uint64(s.P) >= 1<<30 ||
+			128*uint64(s.N)*uint64(s.R) > maxScryptMemory {
+			return fmt.Errorf("crypto: invalid scrypt parameters %+v", s)
+		}
+	case PBKDF2SHA256:
+		if h.iterations < 1 || h.iterations > maxPBKDF2Iterations {
+			return fmt.Errorf("crypto: invalid pbkdf2 iteration count %d", h.iterations)
+		}
+	}
+	return nil
+}
+
+func (h passwordHash) derive(password string, keyLen int) ([]byte, error) {
+	switch h.algorithm {
+	case Argon2id:
+		a := h.argon2
+		return argon2.IDKey([]byte(password), h.salt, a.Time, a.Memory, a.Threads, uint32(keyLen)), nil
+	case Scrypt:
+		s := h.scrypt
+		return scrypt.Key([]byte(password), h.salt, s.N, s.R, s.P, keyLen)
+	case PBKDF2SHA256:
+		return pbkdf2.Key([]byte(password), h.salt, h.iterations, keyLen, sha256.New), nil
+	}
+	return nil, fmt.Errorf("crypto: unsupported password algorithm %q", h.algorithm)
+}
+
+// String formats the hash in the PHC string format
+func (h passwordHash) String() string {
+	var params string
+	switch h.algorithm {
+	case Argon2id:
+		params = fmt.Sprintf("v=%d$m=%d,t=%d,p=%d", argon2.Version, h.argon2.Memory, h.argon2.Time, h.argon2.Threads)
+	case Scrypt:
+		params = fmt.Sprintf("ln=%d,r=%d,p=%d", bits.TrailingZeros(uint(h.scrypt.N)), h.scrypt.R, h.scrypt.P)
+	case PBKDF2SHA256:
+		params = fmt.Sprintf("i=%d", h.iterations)
+	}
+	return "$" + string(h.algorithm) + "$" + params + "$" + phcEncoding.EncodeToString(h.salt) + "$" + phcEncoding.EncodeToString(h.key)
+}
+
+// parsePasswordHash decodes a PHC string or a bcrypt hash
+func parsePasswordHash(hash string) (passwordHash, error) {
+	if strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$") {
+		if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+			return passwordHash{}, ErrInvalidHash
+		}
+		return passwordHash{algorithm: Bcrypt}, nil
+	}
+
+	fields := strings.Split(hash, "$")
+	if len(fields) < 5 || fields[0] != "" {
+		return passwordHash{}, ErrInvalidHash
+	}
+	h := passwordHash{algorithm: PasswordAlgorithm(fields[1])}
+	params := fields[2]
+
+	if h.algorithm == Argon2id {
+		// Argon2 carries its version as a separate field
+		if len(fields) != 6 || fields[2] != "v="+strconv.Itoa(argon2.Version) {
+			return passwordHash{}, ErrInvalidHash
+		}
+		params = fields[3]
+		fields = append(fields[:2], fields[3:]...)
+	}
+	if len(fields) != 5 {
+		return passwordHash{}, ErrInvalidHash
+	}
+
+	values := map[string]uint32{}
+	for _, kv := range strings.Split(params, ",") {
+		k, v, ok := strings.Cut(kv, "=")
+		n, err := strconv.ParseUint(v, 10, 32)
+		if !ok || err != nil {
+			return passwordHash{}, ErrInvalidHash
+		}
+		values[k] = uint32(n)
+	}
+
+	switch h.algorithm {
+	case Argon2id:
+		if values["p"] > 255 {
+			return passwordHash{}, ErrInvalidHash
+		}
+		h.argon2 = Argon2Params{Memory: values["m"], Time: values["t"], Threads: uint8(values["p"])}
+	case Scrypt:
+		if values["ln"] < 1 || values["ln"] > 30 {
+			return passwordHash{}, ErrInvalidHash
+		}
+		h.scrypt = ScryptParams{N: 1 << values["ln"], R: int(values["r"]), P: int(values["p"])}
+	case PBKDF2SHA256:
+		h.iterations = int(values["i"])
+	default:
+		return passwordHash{}, ErrInvalidHash
+	}
+	if err := h.validate(); err != nil {
+		return passwordHash{}, ErrInvalidHash
+	}
+
+	var err error
+	if h.salt, err = phcEncoding.DecodeString(fields[3]); err != nil {
+		return passwordHash{}, ErrInvalidHash
+	}
+	if h.key, err = phcEncoding.DecodeString(fields[4]); err != nil || len(h.key) == 0 {
+		return passwordHash{}, ErrInvalidHash
+	}
+	return h, nil
+}
@@ -0,0 +1,154 @@
+package crypto
+
+import (
+	"strings"
+	"testing"
+)
+
+// testPasswordPolicy is DefaultPasswordPolicy with costs low enough for
+// fast tests
+var testPasswordPolicy = PasswordPolicy{
+	Algorithm:        Argon2id,
+	Argon2:           Argon2Params{Memory: 1024, Time: 1, Threads: 1},
+	Scrypt:           ScryptParams{N: 1 << 10, R: 8, P: 1},
+	BcryptCost:       4,
+	PBKDF2Iterations: 1000,
+	SaltLength:       16,
+	KeyLength:        32,
+}
+
+func TestHashPasswordAlgorithms(t *testing.T) {
+	tests := []struct {
+		algorithm PasswordAlgorithm
+		prefix    string
+	}{
+		{Argon2id, "$argon2id$v=19$m=1024,t=1,p=1$"},
+		{Scrypt, "$scrypt$ln=10,r=8,p=1$"},
+		{Bcrypt, "$2a$04$"},
+		{PBKDF2SHA256, "$pbkdf2-sha256$i=1000$"},
+	}
+
+	for _, test := range tests {
+		policy := testPasswordPolicy
+		policy.Algorithm = test.algorithm
+
+		hash, err := HashPasswordWith("correct horse", policy)
+		if err != nil {
+			t.Errorf("HashPasswordWith(%s) returned error: %v", test.algorithm, err)
+			continue
+		}
+		if !strings.HasPrefix(hash, test.prefix) {
+			t.Errorf("HashPasswordWith(%s) = %q; expected prefix %q", test.algorithm, hash, test.prefix)
+		}
+		if !VerifyPassword("correct horse", hash) {
+			t.Errorf("VerifyPassword failed for %s hash %q", test.algorithm, hash)
+		}
+		if VerifyPassword("correct horse!", hash) {
+			t.Errorf("VerifyPassword accepted a wrong password for %s", test.algorithm)
+		}
+		if NeedsRehash(hash, policy) {
+			t.Errorf("NeedsRehash(%q) with its own policy should be false", hash)
+		}
+	}
+}
+
+func TestHashPasswordUsesRandomSalt(t *testing.T) {
+	a, _ := HashPasswordWith("secret", testPasswordPolicy)
+	b, _ := HashPasswordWith("secret", testPasswordPolicy)
+	if a == b {
+		t.Error("hashing the same password twice should give different hashes")
+	}
+}
+
+func TestVerifyPasswordKnownHashes(t *testing.T) {
+	tests := []struct {
+		password string
+		hash     string
+	}{
+		// Reference vector from the argon2 command line tool
+		{"password", "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"},
+	}
+
+	for _, test := range tests {
+		if !VerifyPassword(test.password, test.hash) {
+			t.Errorf("VerifyPassword(%q, %q) = false; expected true", test.password, test.hash)
+		}
+	}
+}
+
+func TestVerifyPasswordLegacyOptIn(t *testing.T) {
+	// Hash from before HashPassword used a password hashing function
+	legacy := "f3ab6d7f779ddc29da66448ff3711af1df1289c9de16f5d76c918d9f3ef63b2e"
+	if VerifyPassword("password123", legacy) {
+		t.Error("VerifyPassword should reject legacy hashes by default")
+	}
+
+	migrating := DefaultPasswordPolicy
+	migrating.AcceptLegacySHA256 = true
+	if !VerifyPasswordWith("password123", legacy, migrating) {
+		t.Error("VerifyPasswordWith should accept a legacy hash when opted in")
+	}
+	if !VerifyPasswordWith("password123", strings.ToUpper(legacy), migrating) {
+		t.Error("VerifyPasswordWith should accept an upper case legacy hash when opted in")
+	}
+	if VerifyPasswordWith("wrong", legacy, migrating) {
+		t.Error("VerifyPasswordWith accepted the wrong password for a legacy hash")
+	}
+}
+
+func TestHashPasswordRejectsExcessiveCost(t *testing.T) {
+	policy := testPasswordPolicy
+	policy.Argon2.Memory = 4 << 20
+	if _, err := HashPasswordWith("secret", policy); err == nil {
+		t.Error("HashPasswordWith should reject 4 GiB of argon2id memory")
+	}
+	if _, err := parsePasswordHash("$argon2id$v=19$m=4294967295,t=1,p=1$c2FsdA$aGFzaA"); err != ErrInvalidHash {
+		t.Errorf("parsePasswordHash with m=4294967295: got %v; expected ErrInvalidHash", err)
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	hash, _ := HashPasswordWith("secret", testPasswordPolicy)
+
+	stronger := testPasswordPolicy
+	stronger.Argon2.Time = 2
+	if !NeedsRehash(hash, stronger) {
+		t.Error("NeedsRehash should be true when the cost changed")
+	}
+
+	other := testPasswordPolicy
+	other.Algorithm = Scrypt
+	if !NeedsRehash(hash, other) {
+		t.Error("NeedsRehash should be true when the algorithm changed")
+	}
+
+	if !NeedsRehash("f3ab6d7f779ddc29da66448ff3711af1df1289c9de16f5d76c918d9f3ef63b2e", testPasswordPolicy) {
+		t.Error("NeedsRehash should be true for legacy hashes")
+	}
+}
+
+func TestVerifyPasswordRejectsMalformedHashes(t *testing.T) {
+	hashes := []string{
+		"",
+		"plaintext",
+		"$argon2id$v=19$m=0,t=0,p=0$c2FsdA$aGFzaA",
+		"$argon2id$v=16$m=1024,t=1,p=1$c2FsdA$aGFzaA",
+		"$scrypt$ln=99,r=8,p=1$c2FsdA$aGFzaA",
+		"$pbkdf2-sha256$i=-1$c2FsdA$aGFzaA",
+		"$md5$i=1$c2FsdA$aGFzaA",
+		"$2a$99$invalid",
+		// Costs beyond the bounds, which would exhaust memory or CPU
+		"$argon2id$v=19$m=4294967295,t=1,p=1$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=65536,t=4294967295,p=1$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=65536,t=1,p=255$c2FsdA$aGFzaA",
+		"$scrypt$ln=30,r=8,p=1$c2FsdA$aGFzaA",
+		"$scrypt$ln=15,r=4294967295,p=1$c2FsdA$aGFzaA",
+		"$pbkdf2-sha256$i=4294967295$c2FsdA$aGFzaA",
+	}
+
+	for _, hash := range hashes {
+		if VerifyPassword("", hash) {
+			t.Errorf("VerifyPassword accepted malformed hash %q", hash)
+		}
+	}
+}
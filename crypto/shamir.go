@@ -0,0 +1,163 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+)
+
+// Share layout: version | threshold | set ID | x | y bytes | checksum
+const (
+	shareVersion     = 1
+	shareSetIDSize   = 8
+	shareHeaderSize  = 2 + shareSetIDSize + 1
+	shareChecksumLen = 4
+)
+
+// ErrInvalidShare is returned when shares are malformed or do not belong
+// together
+var ErrInvalidShare = errors.New("crypto: invalid share")
+
+// Split divides secret into n shares, any k of which recover it with
+// Combine while fewer reveal nothing about it. It uses Shamir's secret
+// sharing over GF(256), one random polynomial of degree k-1 per byte.
+// Each share is URL-safe base64 and records the threshold, a random ID
+// common to the n shares and a checksum, so Combine detects corrupted
+// shares and shares from different splits.
+func Split(secret []byte, n, k int) ([]string, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("crypto: cannot split an empty secret")
+	}
+	if k < 2 || k > n || n > 255 {
+		return nil, fmt.Errorf("crypto: need 2 <= k <= n <= 255, got n=%d k=%d", n, k)
+	}
+
+	setID, err := RandomBytes(shareSetIDSize)
+	if err != nil {
+		return nil, err
+	}
+	// k-1 random coefficients for each byte of the secret
+	random, err := RandomBytes(len(secret) * (k - 1))
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]string, n)
+	for s := 0; s < n; s++ {
+		x := byte(s + 1)
+		share := make([]byte, 0, shareHeaderSize+len(secret)+shareChecksumLen)
+		share = append(share, shareVersion, byte(k))
+		share = append(share, setID...)
+		share = append(share, x)
+		for i, b := range secret {
+			coefficients := random[i*(k-1) : (i+1)*(k-1)]
+			share = append(share, gfEval(b, coefficients, x))
+		}
+		share = append(share, shareChecksum(share)...)
+		shares[s] = Base64RawURL.EncodeToString(share)
+	}
+	return shares, nil
+}
+
+// Combine recovers the secret from at least the threshold number of
+// shares produced by one call to Split
+func Combine(shares []string) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("%w: no shares", ErrInvalidShare)
+	}
+
+	var (
+		xs     []byte
+		ys     [][]byte
+		header []byte
+	)
+	seen := map[byte]bool{}
+	for i, encoded := range shares {
+		share, err := Base64RawURL.DecodeString(encoded)
+		if err != nil || len(share) < shareHeaderSize+1+shareChecksumLen {
+			return nil, fmt.Errorf("%w: share %d is malformed", ErrInvalidShare, i+1)
+		}
+		body, sum := share[:len(share)-shareChecksumLen], share[len(share)-shareChecksumLen:]
+		if subtle.ConstantTimeCompare(sum, shareChecksum(body)) != 1 {
+			return nil, fmt.Errorf("crypto: share %d: %w", i+1, ErrInvalidChecksum)
+		}
+		if body[0] != shareVersion {
+			return nil, fmt.Errorf("%w: share %d has unknown version %d", ErrInvalidShare, i+1, body[0])
+		}
+
+		if header == nil {
+			header = body[:shareHeaderSize-1]
+		} else if !bytes.Equal(body[:shareHeaderSize-1], header) || len(body)-shareHeaderSize != len(ys[0]) {
+			return nil, fmt.Errorf("%w: share %d is from a different split", ErrInvalidShare, i+1)
+		}
+		x := body[shareHeaderSize-1]
+		if seen[x] {
+			continue
+		}
+		seen[x] = true
+		xs = append(xs, x)
+		ys = append(ys, body[shareHeaderSize:])
+	}
+
+	if k := int(header[1]); len(xs) < k {
+		return nil, fmt.Errorf("%w: %d distinct shares given; %d are needed", ErrInvalidShare, len(xs), k)
+	}
+
+	// Lagrange interpolation at x = 0
+	secret := make([]byte, len(ys[0]))
+	for i := range xs {
+		basis := byte(1)
+		for j := range xs {
+			if i != j {
+				basis = gfMul(basis, gfMul(xs[j], gfInv(xs[j]^xs[i])))
+			}
+		}
+		for b := range secret {
+			secret[b] ^= gfMul(ys[i][b], basis)
+		}
+	}
+	return secret, nil
+}
+
+// shareChecksum returns the first bytes of the SHA-256 of a share body
+func shareChecksum(body []byte) []byte {
+	sum := sha256.Sum256(body)
+	return sum[:shareChecksumLen]
+}
+
+// gfEval evaluates the polynomial with constant term intercept and the
+// given higher coefficients at x, by Horner's rule
+func gfEval(intercept byte, coefficients []byte, x byte) byte {
+	var result byte
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		result = gfMul(result, x) ^ coefficients[i]
+	}
+	return gfMul(result, x) ^ intercept
+}
+
+// gfMul multiplies in GF(256) with the AES polynomial, without branches or
+// table lookups that depend on secret data
+func gfMul(a, b byte) byte {
+	var p byte
+	for i := 0; i < 8; i++ {
+		p ^= -(b & 1) & a
+		a = a<<1 ^ (-(a >> 7) & 0x1b)
+		b >>= 1
+	}
+	return p
+}
+
+// gfInv returns the multiplicative inverse of a non-zero a, which is
+// a^254 in GF(256)
+func gfInv(a byte) byte {
+	result := byte(1)
+	for exp := 254; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			result = gfMul(result, a)
+		}
+		a = gfMul(a, a)
+	}
+	return result
+}
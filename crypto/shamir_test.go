@@ -0,0 +1,94 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestGF256(t *testing.T) {
+	// The AES field: 0x53 * 0xca = 1
+	if gfMul(0x53, 0xca) != 1 {
+		t.Errorf("gfMul(0x53, 0xca) = %#x; expected 1", gfMul(0x53, 0xca))
+	}
+	for a := 1; a < 256; a++ {
+		if gfMul(byte(a), gfInv(byte(a))) != 1 {
+			t.Fatalf("gfInv(%#x) is not an inverse", a)
+		}
+	}
+}
+
+func TestSplitAndCombine(t *testing.T) {
+	secret := []byte("master keyring backup passphrase")
+	shares, err := Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("Split returned error: %v", err)
+	}
+	if len(shares) != 5 {
+		t.Fatalf("Split returned %d shares; expected 5", len(shares))
+	}
+
+	// Every subset of 3 or more shares recovers the secret
+	for mask := 0; mask < 1<<5; mask++ {
+		var subset []string
+		for i := range shares {
+			if mask&(1<<i) != 0 {
+				subset = append(subset, shares[i])
+			}
+		}
+		if len(subset) < 3 {
+			continue
+		}
+		recovered, err := Combine(subset)
+		if err != nil {
+			t.Fatalf("Combine(%05b) returned error: %v", mask, err)
+		}
+		if !bytes.Equal(recovered, secret) {
+			t.Errorf("Combine(%05b) = %q", mask, recovered)
+		}
+	}
+
+	if _, err := Combine(shares[:2]); !errors.Is(err, ErrInvalidShare) {
+		t.Errorf("Combine with too few shares: got %v; expected ErrInvalidShare", err)
+	}
+	if _, err := Combine([]string{shares[0], shares[0], shares[1]}); !errors.Is(err, ErrInvalidShare) {
+		t.Errorf("Combine with a repeated share: got %v; expected ErrInvalidShare", err)
+	}
+}
+
+func TestCombineDetectsBadShares(t *testing.T) {
+	secret, _ := RandomBytes(32)
+	shares, _ := Split(secret, 3, 2)
+	other, _ := Split(secret, 3, 2)
+
+	raw, _ := Base64RawURL.DecodeString(shares[1])
+	raw[len(raw)/2] ^= 0x01
+	corrupted := Base64RawURL.EncodeToString(raw)
+	if _, err := Combine([]string{shares[0], corrupted}); !errors.Is(err, ErrInvalidChecksum) {
+		t.Errorf("Combine with a corrupted share: got %v; expected ErrInvalidChecksum", err)
+	}
+
+	if _, err := Combine([]string{shares[0], other[1]}); !errors.Is(err, ErrInvalidShare) {
+		t.Errorf("Combine with shares from different splits: got %v; expected ErrInvalidShare", err)
+	}
+	if _, err := Combine([]string{shares[0], "not a share"}); !errors.Is(err, ErrInvalidShare) {
+		t.Errorf("Combine with garbage: got %v; expected ErrInvalidShare", err)
+	}
+}
+
+func TestSplitErrors(t *testing.T) {
+	invalid := []struct {
+		secret []byte
+		n, k   int
+	}{
+		{nil, 3, 2},
+		{[]byte("x"), 3, 1},
+		{[]byte("x"), 2, 3},
+		{[]byte("x"), 256, 2},
+	}
+	for _, test := range invalid {
+		if _, err := Split(test.secret, test.n, test.k); err == nil {
+			t.Errorf("Split(%q, %d, %d) should fail", test.secret, test.n, test.k)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HMACSHA256 returns the HMAC-SHA256 of message under key
+func HMACSHA256(key, message []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(message)
+	return mac.Sum(nil)
+}
+
+// HMACSHA512 returns the HMAC-SHA512 of message under key
+func HMACSHA512(key, message []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(message)
+	return mac.Sum(nil)
+}
+
+// VerifyHMACSHA256 reports whether mac is the HMAC-SHA256 of message under
+// key, comparing in constant time
+func VerifyHMACSHA256(key, message, mac []byte) bool {
+	return hmac.Equal(HMACSHA256(key, message), mac)
+}
+
+// VerifyHMACSHA512 reports whether mac is the HMAC-SHA512 of message under
+// key, comparing in constant time
+func VerifyHMACSHA512(key, message, mac []byte) bool {
+	return hmac.Equal(HMACSHA512(key, message), mac)
+}
+
+var (
+	// ErrInvalidToken is returned for a token that is malformed or whose
+	// signature does not match
+	ErrInvalidToken = errors.New("crypto: invalid signed token")
+	// ErrTokenExpired is returned for a correctly signed token or URL past
+	// its expiry
+	ErrTokenExpired = errors.New("crypto: signed token expired")
+)
+
+// tokenEncoding is URL-safe base64 without padding, so tokens can be used in
+// URLs and headers unescaped
+var tokenEncoding = base64.RawURLEncoding
+
+// SignedToken is a tamper-proof payload with an optional expiry. Its
+// encoded form is "<body>.<signature>" in URL-safe base64, where the body
+// carries the key ID, expiry and payload. The payload is signed, not
+// encrypted.
+type SignedToken struct {
+	Payload []byte
+	// ExpiresAt is the time after which the token is rejected. The zero
+	// time means the token does not expire.
+	ExpiresAt time.Time
+	// KeyID names the signing key so verifiers can pick it from a Keyring
+	KeyID string
+}
+
+const tokenVersion = 1
+
+// SignToken encodes and signs token with HMAC-SHA256
+func SignToken(key []byte, token SignedToken) (string, error) {
+	if len(token.KeyID) > 255 {
+		return "", errors.New("crypto: token key ID is longer than 255 bytes")
+	}
+	var expires int64
+	if !token.ExpiresAt.IsZero() {
+		expires = token.ExpiresAt.Unix()
+	}
+
+	body := make([]byte, 0, 2+len(token.KeyID)+8+len(token.Payload))
+	body = append(body, tokenVersion, byte(len(token.KeyID)))
+	body = append(body, token.KeyID...)
+	body = binary.BigEndian.AppendUint64(body, uint64(expires))
+	body = append(body, token.Payload...)
+
+	encoded := tokenEncoding.EncodeToString(body)
+	return encoded + "." + tokenEncoding.EncodeToString(HMACSHA256(key, []byte(encoded))), nil
+}
+
+// ParseToken decodes a token without checking its signature, typically to
+// read the key ID before VerifyToken. Never trust the payload it returns.
+func ParseToken(s string) (SignedToken, error) {
+	encoded, _, ok := strings.Cut(s, ".")
+	if !ok {
+		return SignedToken{}, ErrInvalidToken
+	}
+	body, err := tokenEncoding.DecodeString(encoded)
+	if err != nil || len(body) < 2 || body[0] != tokenVersion {
+		return SignedToken{}, ErrInvalidToken
+	}
+	n := 2 + int(body[1])
+	if len(body) < n+8 {
+		return SignedToken{}, ErrInvalidToken
+	}
+
+	token := SignedToken{KeyID: string(body[2:n]), Payload: body[n+8:]}
+	if expires := int64(binary.BigEndian.Uint64(body[n : n+8])); expires != 0 {
+		token.ExpiresAt = time.Unix(expires, 0).UTC()
+	}
+	return token, nil
+}
+
+// VerifyToken checks the signature and expiry of a token made by SignToken
+// and returns its contents. It returns ErrInvalidToken if the token was
+// altered or signed with another key and ErrTokenExpired once it expired.
+func VerifyToken(key []byte, s string) (SignedToken, error) {
+	encoded, signature, ok := strings.Cut(s, ".")
+	if !ok {
+		return SignedToken{}, ErrInvalidToken
+	}
+	mac, err := tokenEncoding.DecodeString(signature)
+	if err != nil || !VerifyHMACSHA256(key, []byte(encoded), mac) {
+		return SignedToken{}, ErrInvalidToken
+	}
+
+	token, err := ParseToken(s)
+	if err != nil {
+		return SignedToken{}, err
+	}
+	if !token.ExpiresAt.IsZero() && time.Now().After(token.ExpiresAt) {
+		return SignedToken{}, ErrTokenExpired
+	}
+	return token, nil
+}
+
+// SignToken signs a token carrying payload with a subkey derived from the
+// primary key. A positive ttl sets the expiry.
+func (k *Keyring) SignToken(payload []byte, ttl time.Duration) (string, error) {
+	key, err := k.Primary()
+	if err != nil {
+		return "", err
+	}
+	token := SignedToken{Payload: payload, KeyID: key.ID}
+	if ttl > 0 {
+		token.ExpiresAt = time.Now().Add(ttl)
+	}
+	signingKey, err := tokenSigningKey(key)
+	if err != nil {
+		return "", err
+	}
+	return SignToken(signingKey, token)
+}
+
+// VerifyToken verifies a token signed with any active key of the keyring
+func (k *Keyring) VerifyToken(s string) (SignedToken, error) {
+	token, err := ParseToken(s)
+	if err != nil {
+		return SignedToken{}, err
+	}
+	key, err := k.active(token.KeyID)
+	if err != nil {
+		return SignedToken{}, ErrInvalidToken
+	}
+	signingKey, err := tokenSigningKey(key)
+	if err != nil {
+		return SignedToken{}, err
+	}
+	return VerifyToken(signingKey, s)
+}
+
+// tokenSigningInfo separates the token signing subkey from other uses of
+// keyring material, such as encryption
+var tokenSigningInfo = []byte("goutils token signing v1")
+
+// tokenSigningKey derives the HMAC key for keyring tokens from a key, so
+// the material is never used directly for both encryption and signing
+func tokenSigningKey(key Key) ([]byte, error) {
+	return HKDF(key.Material, nil, tokenSigningInfo, 32)
+}
+
+// Query parameters added by SignURL
+const (
+	urlExpiresParam   = "expires"
+	urlSignatureParam = "signature"
+)
+
+// SignURL adds an expiry and an HMAC-SHA256 signature to rawURL, for
+// example to share a download link that stops working after a while. The
+// signature covers the path and every query parameter but not the scheme
+// and host, so links survive being served behind a proxy.
+func SignURL(key []byte, rawURL string, expires time.Time) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Del(urlSignatureParam)
+	query.Set(urlExpiresParam, strconv.FormatInt(expires.Unix(), 10))
+
+	signature := HMACSHA256(key, urlSigningInput(u, query))
+	query.Set(urlSignatureParam, tokenEncoding.EncodeToString(signature))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// VerifyURL checks a URL made by SignURL. It returns ErrInvalidToken if the
+// URL was altered or signed with another key and ErrTokenExpired once it
+// expired.
+func VerifyURL(key []byte, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ErrInvalidToken
+	}
+	query := u.Query()
+	signature, err := tokenEncoding.DecodeString(query.Get(urlSignatureParam))
+	if err != nil {
+		return ErrInvalidToken
+	}
+	query.Del(urlSignatureParam)
+	if !VerifyHMACSHA256(key, urlSigningInput(u, query), signature) {
+		return ErrInvalidToken
+	}
+
+	expires, err := strconv.ParseInt(query.Get(urlExpiresParam), 10, 64)
+	if err != nil {
+		return ErrInvalidToken
+	}
+	if time.Now().After(time.Unix(expires, 0)) {
+		return ErrTokenExpired
+	}
+	return nil
+}
+
+// urlSigningInput is the canonical form of a URL for signing: the escaped
+// path and the query parameters in sorted order
+func urlSigningInput(u *url.URL, query url.Values) []byte {
+	return []byte(u.EscapedPath() + "?" + query.Encode())
+}
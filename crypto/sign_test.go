@@ -0,0 +1,144 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHMAC(t *testing.T) {
+	// RFC 4231 test case 2
+	key := []byte("Jefe")
+	message := []byte("what do ya want for nothing?")
+	sha256Expected := "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	sha512Expected := "164b7a7bfcf819e2e395fbe73b56e0a387bd64222e831fd610270cd7ea2505549758bf75c05a994a6d034f65f8f0e6fdcaeab1a34d4a6b4b636e070a38bce737"
+
+	if result := hex.EncodeToString(HMACSHA256(key, message)); result != sha256Expected {
+		t.Errorf("HMACSHA256 = %s; expected %s", result, sha256Expected)
+	}
+	if result := hex.EncodeToString(HMACSHA512(key, message)); result != sha512Expected {
+		t.Errorf("HMACSHA512 = %s; expected %s", result, sha512Expected)
+	}
+
+	mac := HMACSHA256(key, message)
+	if !VerifyHMACSHA256(key, message, mac) {
+		t.Error("VerifyHMACSHA256 rejected a valid MAC")
+	}
+	if VerifyHMACSHA256(key, []byte("what do ya want for something?"), mac) {
+		t.Error("VerifyHMACSHA256 accepted a MAC for another message")
+	}
+	if VerifyHMACSHA512(key, message, HMACSHA512([]byte("other"), message)) {
+		t.Error("VerifyHMACSHA512 accepted a MAC under another key")
+	}
+}
+
+func TestSignedToken(t *testing.T) {
+	key := []byte("webhook-signing-key")
+	token, err := SignToken(key, SignedToken{
+		Payload:   []byte(`{"user":42}`),
+		ExpiresAt: time.Now().Add(time.Hour),
+		KeyID:     "wh1",
+	})
+	if err != nil {
+		t.Fatalf("SignToken returned error: %v", err)
+	}
+	if strings.ContainsAny(token, "+/=") {
+		t.Errorf("SignToken = %q; expected URL-safe base64", token)
+	}
+
+	verified, err := VerifyToken(key, token)
+	if err != nil || string(verified.Payload) != `{"user":42}` || verified.KeyID != "wh1" {
+		t.Errorf("VerifyToken = %+v, %v", verified, err)
+	}
+
+	if _, err := VerifyToken([]byte("other-key"), token); err != ErrInvalidToken {
+		t.Errorf("VerifyToken with another key: got %v; expected ErrInvalidToken", err)
+	}
+	body, signature, _ := strings.Cut(token, ".")
+	forged := body[:len(body)-2] + "AA." + signature
+	if _, err := VerifyToken(key, forged); err != ErrInvalidToken {
+		t.Errorf("VerifyToken of an altered token: got %v; expected ErrInvalidToken", err)
+	}
+
+	expired, _ := SignToken(key, SignedToken{Payload: []byte("x"), ExpiresAt: time.Now().Add(-time.Minute)})
+	if _, err := VerifyToken(key, expired); err != ErrTokenExpired {
+		t.Errorf("VerifyToken of an expired token: got %v; expected ErrTokenExpired", err)
+	}
+
+	forever, _ := SignToken(key, SignedToken{Payload: []byte("x")})
+	if verified, err := VerifyToken(key, forever); err != nil || !verified.ExpiresAt.IsZero() {
+		t.Errorf("VerifyToken of a token without expiry = %+v, %v", verified, err)
+	}
+
+	for _, bad := range []string{"", "nodot", ".", "!!.!!"} {
+		if _, err := VerifyToken(key, bad); err != ErrInvalidToken {
+			t.Errorf("VerifyToken(%q) = %v; expected ErrInvalidToken", bad, err)
+		}
+	}
+}
+
+func TestKeyringSignedToken(t *testing.T) {
+	ring := NewKeyring()
+	ring.Rotate("old", 0)
+	token, err := ring.SignToken([]byte("share:123"), time.Hour)
+	if err != nil {
+		t.Fatalf("SignToken returned error: %v", err)
+	}
+
+	ring.Rotate("new", 0)
+	if verified, err := ring.VerifyToken(token); err != nil || verified.KeyID != "old" {
+		t.Errorf("VerifyToken after rotation = %+v, %v", verified, err)
+	}
+
+	// Tokens are signed with a subkey, not the raw key material
+	old, _ := ring.Get("old")
+	if _, err := VerifyToken(old.Material, token); err != ErrInvalidToken {
+		t.Errorf("VerifyToken with the raw key material: got %v; expected ErrInvalidToken", err)
+	}
+	subkey, _ := HKDF(old.Material, nil, []byte("goutils token signing v1"), 32)
+	if _, err := VerifyToken(subkey, token); err != nil {
+		t.Errorf("VerifyToken with the derived subkey returned error: %v", err)
+	}
+
+	ring.Deactivate("old")
+	if _, err := ring.VerifyToken(token); err != ErrInvalidToken {
+		t.Errorf("VerifyToken under a deactivated key: got %v; expected ErrInvalidToken", err)
+	}
+}
+
+func TestSignURL(t *testing.T) {
+	key := []byte("download-key")
+	signed, err := SignURL(key, "https://cdn.example.com/files/report.pdf?user=7", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("SignURL returned error: %v", err)
+	}
+	if !strings.Contains(signed, "expires=") || !strings.Contains(signed, "signature=") {
+		t.Errorf("SignURL = %q; expected expires and signature parameters", signed)
+	}
+	if err := VerifyURL(key, signed); err != nil {
+		t.Errorf("VerifyURL(%q) returned error: %v", signed, err)
+	}
+
+	// The host is not signed, so the link works behind another domain
+	moved := strings.Replace(signed, "cdn.example.com", "files.example.net", 1)
+	if err := VerifyURL(key, moved); err != nil {
+		t.Errorf("VerifyURL on another host returned error: %v", err)
+	}
+
+	for _, tampered := range []string{
+		strings.Replace(signed, "user=7", "user=8", 1),
+		strings.Replace(signed, "report.pdf", "secret.pdf", 1),
+		strings.Replace(signed, "expires=", "expires=9", 1),
+		strings.Replace(signed, "signature=", "x=", 1),
+	} {
+		if err := VerifyURL(key, tampered); err != ErrInvalidToken {
+			t.Errorf("VerifyURL(%q) = %v; expected ErrInvalidToken", tampered, err)
+		}
+	}
+
+	expired, _ := SignURL(key, "https://cdn.example.com/a", time.Now().Add(-time.Second))
+	if err := VerifyURL(key, expired); err != ErrTokenExpired {
+		t.Errorf("VerifyURL of an expired link: got %v; expected ErrTokenExpired", err)
+	}
+}
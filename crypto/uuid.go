@@ -0,0 +1,270 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Well-known namespaces for name-based (version 5) UUIDs, from RFC 4122
+const (
+	UUIDNamespaceDNS  = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	UUIDNamespaceURL  = "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+	UUIDNamespaceOID  = "6ba7b812-9dad-11d1-80b4-00c04fd430c8"
+	UUIDNamespaceX500 = "6ba7b814-9dad-11d1-80b4-00c04fd430c8"
+)
+
+// ErrInvalidULID is returned when a string is not a valid ULID
+var ErrInvalidULID = errors.New("crypto: invalid ULID")
+
+// crockfordAlphabet is the Crockford base32 alphabet used by ULIDs
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// gregorianOffset is the number of 100ns intervals between the UUID epoch,
+// 1582-10-15, and the Unix epoch
+const gregorianOffset = 122192928000000000
+
+// GenerateUUIDv5 returns the deterministic name-based UUID of name within
+// namespace, which must itself be a UUID such as UUIDNamespaceURL
+func GenerateUUIDv5(namespace, name string) (string, error) {
+	space, err := uuid.Parse(namespace)
+	if err != nil {
+		return "", fmt.Errorf("crypto: invalid UUID namespace: %w", err)
+	}
+	return uuid.NewSHA1(space, []byte(name)).String(), nil
+}
+
+// GenerateUUIDv6 returns a time-ordered UUID holding the current time in
+// 100ns intervals, with a random clock sequence and node
+func GenerateUUIDv6() (string, error) {
+	var u uuid.UUID
+	if _, err := io.ReadFull(rand.Reader, u[8:]); err != nil {
+		return "", err
+	}
+	ts := uint64(time.Now().UnixNano()/100) + gregorianOffset
+	binary.BigEndian.PutUint32(u[0:], uint32(ts>>28))
+	binary.BigEndian.PutUint16(u[4:], uint16(ts>>12))
+	binary.BigEndian.PutUint16(u[6:], 0x6000|uint16(ts&0x0fff))
+	u[8] = 0x80 | u[8]&0x3f
+	return u.String(), nil
+}
+
+// GenerateUUIDv7 returns a time-ordered UUID holding the current Unix time
+// in milliseconds followed by 74 random bits. UUIDs from the same
+// millisecond are not ordered; use a MonotonicGenerator for that.
+func GenerateUUIDv7() (string, error) {
+	var entropy [10]byte
+	if _, err := io.ReadFull(rand.Reader, entropy[:]); err != nil {
+		return "", err
+	}
+	hi := binary.BigEndian.Uint16(entropy[:2]) & 0x03ff
+	lo := binary.BigEndian.Uint64(entropy[2:])
+	return uuidV7(uint64(time.Now().UnixMilli()), hi, lo).String(), nil
+}
+
+// GenerateULID returns a ULID: a 48-bit Unix millisecond timestamp and 80
+// random bits, as 26 Crockford base32 characters
+func GenerateULID() (string, error) {
+	var entropy [10]byte
+	if _, err := io.ReadFull(rand.Reader, entropy[:]); err != nil {
+		return "", err
+	}
+	hi := binary.BigEndian.Uint16(entropy[:2])
+	lo := binary.BigEndian.Uint64(entropy[2:])
+	return encodeULID(uint64(time.Now().UnixMilli()), hi, lo), nil
+}
+
+// uuidV7 lays out a millisecond timestamp and 74 bits of entropy, hi
+// holding the top 10, as a version 7 UUID
+func uuidV7(ms uint64, hi uint16, lo uint64) uuid.UUID {
+	var u uuid.UUID
+	binary.BigEndian.PutUint64(u[0:], ms<<16)
+	randA := uint16(hi)<<2 | uint16(lo>>62)
+	binary.BigEndian.PutUint16(u[6:], 0x7000|randA&0x0fff)
+	binary.BigEndian.PutUint64(u[8:], 0x8000000000000000|lo&0x3fffffffffffffff)
+	return u
+}
+
+// encodeULID encodes a millisecond timestamp and 80 bits of entropy, hi
+// holding the top 16, as a ULID
+func encodeULID(ms uint64, hi uint16, lo uint64) string {
+	// The 128 bits as two words; the first character holds the top 3 bits
+	w1 := ms<<16 | uint64(hi)
+	w0 := lo
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockfordAlphabet[w0&31]
+		w0 = w0>>5 | w1<<59
+		w1 >>= 5
+	}
+	return string(out[:])
+}
+
+// UUIDInfo describes a parsed UUID
+type UUIDInfo struct {
+	// UUID is the canonical lowercase form
+	UUID    string
+	Version int
+	// Variant is "RFC4122", "Reserved", "Microsoft" or "Future"
+	Variant string
+	// Time is the embedded timestamp of version 1, 6 and 7 UUIDs, and
+	// the zero time otherwise
+	Time time.Time
+}
+
+// ParseUUID parses a UUID in the canonical form, with or without braces or
+// a "urn:uuid:" prefix, and reports its version, variant and timestamp
+func ParseUUID(s string) (UUIDInfo, error) {
+	u, err := uuid.Parse(s)
+	if err != nil {
+		return UUIDInfo{}, fmt.Errorf("crypto: invalid UUID: %w", err)
+	}
+
+	info := UUIDInfo{UUID: u.String(), Version: int(u.Version()), Variant: u.Variant().String()}
+	if u.Variant() != uuid.RFC4122 {
+		return info, nil
+	}
+	switch info.Version {
+	case 1:
+		ts := uint64(binary.BigEndian.Uint16(u[6:])&0x0fff)<<48 |
+			uint64(binary.BigEndian.Uint16(u[4:]))<<32 |
+			uint64(binary.BigEndian.Uint32(u[0:]))
+		info.Time = gregorianTime(ts)
+	case 6:
+		ts := uint64(binary.BigEndian.Uint32(u[0:]))<<28 |
+			uint64(binary.BigEndian.Uint16(u[4:]))<<12 |
+			uint64(binary.BigEndian.Uint16(u[6:])&0x0fff)
+		info.Time = gregorianTime(ts)
+	case 7:
+		info.Time = time.UnixMilli(int64(binary.BigEndian.Uint64(u[0:]) >> 16))
+	}
+	return info, nil
+}
+
+// gregorianTime converts a count of 100ns intervals since the UUID epoch
+func gregorianTime(ts uint64) time.Time {
+	ns := (int64(ts) - gregorianOffset) * 100
+	return time.Unix(0, ns)
+}
+
+// IsUUID reports whether s is a valid UUID of any version
+func IsUUID(s string) bool {
+	_, err := uuid.Parse(s)
+	return err == nil
+}
+
+// ParseULID decodes a ULID, case-insensitively, and returns its timestamp
+// and 80 bits of entropy
+func ParseULID(s string) (time.Time, []byte, error) {
+	if len(s) != 26 {
+		return time.Time{}, nil, ErrInvalidULID
+	}
+	var w1, w0 uint64
+	for i := 0; i < len(s); i++ {
+		v := strings.IndexByte(crockfordAlphabet, upperASCII(s[i]))
+		// The first character may only hold 3 bits
+		if v < 0 || (i == 0 && v > 7) {
+			return time.Time{}, nil, ErrInvalidULID
+		}
+		w1 = w1<<5 | w0>>59
+		w0 = w0<<5 | uint64(v)
+	}
+
+	entropy := make([]byte, 10)
+	binary.BigEndian.PutUint16(entropy, uint16(w1))
+	binary.BigEndian.PutUint64(entropy[2:], w0)
+	return time.UnixMilli(int64(w1 >> 16)), entropy, nil
+}
+
+// upperASCII upper-cases an ASCII letter
+func upperASCII(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+// IsULID reports whether s is a valid ULID
+func IsULID(s string) bool {
+	_, _, err := ParseULID(s)
+	return err == nil
+}
+
+// MonotonicGenerator generates version 7 UUIDs and ULIDs that sort in
+// generation order, even within a single millisecond. Within the same
+// millisecond the random part of the previous value is incremented instead
+// of drawn afresh; if it overflows, or the clock moves backwards, the
+// timestamp is carried forward from the previous value. UUIDs and ULIDs
+// are ordered independently. It is safe for concurrent use.
+type MonotonicGenerator struct {
+	mu   sync.Mutex
+	now  func() time.Time
+	uuid monotonicState
+	ulid monotonicState
+}
+
+// monotonicState is the last timestamp and entropy handed out
+type monotonicState struct {
+	ms uint64
+	hi uint16
+	lo uint64
+}
+
+// NewMonotonicGenerator returns a MonotonicGenerator using the system clock
+func NewMonotonicGenerator() *MonotonicGenerator {
+	return &MonotonicGenerator{now: time.Now}
+}
+
+// UUIDv7 returns the next version 7 UUID
+func (g *MonotonicGenerator) UUIDv7() (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.uuid.next(uint64(g.now().UnixMilli()), 10); err != nil {
+		return "", err
+	}
+	return uuidV7(g.uuid.ms, g.uuid.hi, g.uuid.lo).String(), nil
+}
+
+// ULID returns the next ULID
+func (g *MonotonicGenerator) ULID() (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.ulid.next(uint64(g.now().UnixMilli()), 16); err != nil {
+		return "", err
+	}
+	return encodeULID(g.ulid.ms, g.ulid.hi, g.ulid.lo), nil
+}
+
+// next advances the state to a value greater than the last one. hiBits is
+// the number of entropy bits kept in hi on top of the 64 in lo.
+func (s *monotonicState) next(ms uint64, hiBits uint) error {
+	max := uint16(1<<hiBits - 1)
+	if ms <= s.ms && s.ms != 0 {
+		s.lo++
+		if s.lo != 0 {
+			return nil
+		}
+		if s.hi < max {
+			s.hi++
+			return nil
+		}
+		ms = s.ms + 1
+	}
+
+	var entropy [10]byte
+	if _, err := io.ReadFull(rand.Reader, entropy[:]); err != nil {
+		return err
+	}
+	s.ms = ms
+	// Keep the top entropy bit clear so a millisecond has room to increment
+	s.hi = binary.BigEndian.Uint16(entropy[:2]) & (max >> 1)
+	s.lo = binary.BigEndian.Uint64(entropy[2:])
+	return nil
+}
@@ -0,0 +1,155 @@
+package crypto
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateUUIDv5(t *testing.T) {
+	id, err := GenerateUUIDv5(UUIDNamespaceDNS, "www.example.com")
+	if err != nil {
+		t.Fatalf("GenerateUUIDv5 returned error: %v", err)
+	}
+	if id != "2ed6657d-e927-568b-95e1-2665a8aea6a2" {
+		t.Errorf("GenerateUUIDv5 = %s; expected 2ed6657d-e927-568b-95e1-2665a8aea6a2", id)
+	}
+	if again, _ := GenerateUUIDv5(UUIDNamespaceDNS, "www.example.com"); again != id {
+		t.Error("GenerateUUIDv5 should be deterministic")
+	}
+	if _, err := GenerateUUIDv5("not-a-uuid", "x"); err == nil {
+		t.Error("GenerateUUIDv5 should reject an invalid namespace")
+	}
+}
+
+func TestGenerateTimeOrderedUUIDs(t *testing.T) {
+	before := time.Now().Add(-time.Millisecond)
+
+	tests := []struct {
+		version  int
+		generate func() (string, error)
+	}{
+		{6, GenerateUUIDv6},
+		{7, GenerateUUIDv7},
+	}
+	for _, test := range tests {
+		id, err := test.generate()
+		if err != nil {
+			t.Fatalf("GenerateUUIDv%d returned error: %v", test.version, err)
+		}
+		info, err := ParseUUID(id)
+		if err != nil {
+			t.Fatalf("ParseUUID(%s) returned error: %v", id, err)
+		}
+		if info.Version != test.version || info.Variant != "RFC4122" {
+			t.Errorf("GenerateUUIDv%d produced version %d variant %s", test.version, info.Version, info.Variant)
+		}
+		if info.Time.Before(before) || info.Time.After(time.Now()) {
+			t.Errorf("GenerateUUIDv%d embedded time %v", test.version, info.Time)
+		}
+	}
+}
+
+func TestParseUUID(t *testing.T) {
+	// Examples from RFC 9562 appendix A, all for 2022-02-22 19:22:22 UTC
+	stamp := time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)
+
+	tests := []struct {
+		input   string
+		version int
+		time    time.Time
+	}{
+		{"C232AB00-9414-11EC-B3C8-9F6BDECED846", 1, stamp},
+		{"1EC9414C-232A-6B00-B3C8-9F6BDECED846", 6, stamp},
+		{"017F22E2-79B0-7CC3-98C4-DC0C0C07398F", 7, stamp},
+		{"urn:uuid:919108f7-52d1-4320-9bac-f847db4148a8", 4, time.Time{}},
+		{"{2ed6657d-e927-568b-95e1-2665a8aea6a2}", 5, time.Time{}},
+	}
+
+	for _, test := range tests {
+		info, err := ParseUUID(test.input)
+		if err != nil {
+			t.Errorf("ParseUUID(%s) returned error: %v", test.input, err)
+			continue
+		}
+		if info.Version != test.version || !info.Time.Equal(test.time) {
+			t.Errorf("ParseUUID(%s) = version %d time %v; expected version %d time %v",
+				test.input, info.Version, info.Time, test.version, test.time)
+		}
+		if info.UUID != strings.ToLower(info.UUID) || len(info.UUID) != 36 {
+			t.Errorf("ParseUUID(%s) canonical form %s", test.input, info.UUID)
+		}
+	}
+
+	if IsUUID("017F22E2-79B0-7CC3-98C4") || !IsUUID(GenerateUUID()) {
+		t.Error("IsUUID gave the wrong answer")
+	}
+}
+
+func TestULID(t *testing.T) {
+	// Example from the ULID specification
+	ts, entropy, err := ParseULID("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	if err != nil {
+		t.Fatalf("ParseULID returned error: %v", err)
+	}
+	if ts.UnixMilli() != 1469922850259 || len(entropy) != 10 {
+		t.Errorf("ParseULID = %d, %x", ts.UnixMilli(), entropy)
+	}
+	if lower, _, _ := ParseULID("01arz3ndektsv4rrffq69g5fav"); !lower.Equal(ts) {
+		t.Error("ParseULID should be case-insensitive")
+	}
+	if encoded := encodeULID(1469918176385, 0, 0); encoded != "01ARYZ6S410000000000000000" {
+		t.Errorf("encodeULID = %s; expected 01ARYZ6S410000000000000000", encoded)
+	}
+
+	id, err := GenerateULID()
+	if err != nil {
+		t.Fatalf("GenerateULID returned error: %v", err)
+	}
+	if len(id) != 26 || !IsULID(id) {
+		t.Errorf("GenerateULID = %s", id)
+	}
+	if ts, _, _ := ParseULID(id); time.Since(ts) > time.Second {
+		t.Errorf("GenerateULID embedded time %v", ts)
+	}
+
+	for _, bad := range []string{"", "01ARZ3NDEKTSV4RRFFQ69G5FA", "01ARZ3NDEKTSV4RRFFQ69G5FAU", "81ARZ3NDEKTSV4RRFFQ69G5FAV"} {
+		if IsULID(bad) {
+			t.Errorf("IsULID(%q) should be false", bad)
+		}
+	}
+}
+
+func TestMonotonicGenerator(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+	g := NewMonotonicGenerator()
+	g.now = func() time.Time { return now }
+
+	var lastUUID, lastULID string
+	for i := 0; i < 10000; i++ {
+		// The clock stalls and then moves backwards half way through
+		if i == 5000 {
+			now = now.Add(-time.Second)
+		}
+		u, err := g.UUIDv7()
+		if err != nil {
+			t.Fatalf("UUIDv7 returned error: %v", err)
+		}
+		l, err := g.ULID()
+		if err != nil {
+			t.Fatalf("ULID returned error: %v", err)
+		}
+		if u <= lastUUID || l <= lastULID {
+			t.Fatalf("generator went backwards at %d: %s after %s, %s after %s", i, u, lastUUID, l, lastULID)
+		}
+		lastUUID, lastULID = u, l
+	}
+
+	// An exhausted millisecond carries over into the next one
+	g.ulid.hi, g.ulid.lo = 0xffff, ^uint64(0)
+	next, _ := g.ULID()
+	ts, _, _ := ParseULID(next)
+	if next <= lastULID || uint64(ts.UnixMilli()) != g.ulid.ms || g.ulid.ms != 1700000000001 {
+		t.Errorf("ULID after overflow = %s at %d", next, ts.UnixMilli())
+	}
+}
@@ -3,3 +3,16 @@ module github.com/yourusername/goutils
 go 1.19
 
 require github.com/google/uuid v1.3.0
+
+require (
+	github.com/cespare/xxhash/v2 v2.3.0
+	golang.org/x/text v0.22.0
+	lukechampine.com/blake3 v1.2.1
+)
+
+require github.com/klauspost/cpuid/v2 v2.0.9 // indirect
+
+require (
+	golang.org/x/crypto v0.24.0
+	golang.org/x/sys v0.21.0 // indirect
+)
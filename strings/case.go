@@ -0,0 +1,149 @@
+package strings
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Words splits a string into words for case conversion.
+//
+// Words are separated by whitespace, punctuation and symbols such as '-',
+// '_' and '.', and by case changes inside a run of letters: "helloWorld"
+// yields "hello" and "World", while an acronym ends before the final
+// capital of an upper-to-lower transition, so "HTTPServer" yields "HTTP"
+// and "Server". Digits stay attached to the word they follow ("Request2"),
+// and a word starting with digits keeps the letters after them ("2FA").
+// Apostrophes are dropped without splitting, so "don't" yields "dont".
+//
+// Words splits the output of every converter in this file back into the
+// same words, so converting from one form to another gives the same result
+// as converting the original string.
+func Words(s string) []string {
+	var words []string
+	var word []rune
+
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = word[:0]
+		}
+	}
+
+	runes := []rune(s)
+	for i, r := range runes {
+		if r == '\'' || r == '’' {
+			continue
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r) {
+			flush()
+			continue
+		}
+		if len(word) > 0 && unicode.IsUpper(r) {
+			prev := word[len(word)-1]
+			switch {
+			case unicode.IsLower(prev):
+				// "helloWorld"
+				flush()
+			case unicode.IsDigit(prev) && strings.IndexFunc(string(word), unicode.IsLetter) >= 0:
+				// "user2Name", but not "2FA", whose word starts with digits
+				flush()
+			case unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+				// "HTTPServer": the S starts a new word
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+
+	return words
+}
+
+// joinWords lowercases every word, applies title case to the words selected
+// by title, and joins them with sep. With an empty sep, words that Words
+// would not split apart again are joined with an underscore instead.
+func joinWords(s, sep string, title func(i int) bool) string {
+	words := Words(s)
+	for i, w := range words {
+		w = strings.ToLower(w)
+		if title(i) {
+			w = upperFirst(w)
+		}
+		words[i] = w
+	}
+	if sep != "" {
+		return strings.Join(words, sep)
+	}
+
+	var b strings.Builder
+	for i, w := range words {
+		if i > 0 && !splitsApart(words[i-1], w) {
+			b.WriteByte('_')
+		}
+		b.WriteString(w)
+	}
+	return b.String()
+}
+
+// splitsApart reports whether Words splits a+b into exactly a and b
+func splitsApart(a, b string) bool {
+	words := Words(a + b)
+	return len(words) == 2 && words[0] == a && words[1] == b
+}
+
+// upperFirst converts the first rune of a string to title case
+func upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToTitle(r)) + s[size:]
+}
+
+func never(int) bool      { return false }
+func always(int) bool     { return true }
+func notFirst(i int) bool { return i > 0 }
+
+// CamelCase converts a string to camelCase. Words that would run together
+// are kept apart with an underscore: "a b c" becomes "aB_C" and "user 2fa"
+// becomes "user_2fa".
+func CamelCase(s string) string {
+	return joinWords(s, "", notFirst)
+}
+
+// PascalCase converts a string to PascalCase, keeping words that would run
+// together apart with an underscore as CamelCase does
+func PascalCase(s string) string {
+	return joinWords(s, "", always)
+}
+
+// SnakeCase converts a string to snake_case
+func SnakeCase(s string) string {
+	return joinWords(s, "_", never)
+}
+
+// ScreamingSnakeCase converts a string to SCREAMING_SNAKE_CASE
+func ScreamingSnakeCase(s string) string {
+	return strings.ToUpper(SnakeCase(s))
+}
+
+// KebabCase converts a string to kebab-case
+func KebabCase(s string) string {
+	return joinWords(s, "-", never)
+}
+
+// TrainCase converts a string to Train-Case
+func TrainCase(s string) string {
+	return joinWords(s, "-", always)
+}
+
+// DotCase converts a string to dot.case
+func DotCase(s string) string {
+	return joinWords(s, ".", never)
+}
+
+// TitleCase converts a string to Title Case, one space between words
+func TitleCase(s string) string {
+	return joinWords(s, " ", always)
+}
@@ -0,0 +1,110 @@
+package strings
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"hello world", []string{"hello", "world"}},
+		{"helloWorld", []string{"hello", "World"}},
+		{"HTTPServer", []string{"HTTP", "Server"}},
+		{"XMLHttpRequest2", []string{"XML", "Http", "Request2"}},
+		{"user-id", []string{"user", "id"}},
+		{"user_id.value", []string{"user", "id", "value"}},
+		{"ÉtéBrûlant", []string{"Été", "Brûlant"}},
+		{"user2Name", []string{"user2", "Name"}},
+		{"don't stop", []string{"dont", "stop"}},
+		{"a b c", []string{"a", "b", "c"}},
+		{"aBC", []string{"a", "BC"}},
+		{"ABc", []string{"A", "Bc"}},
+		{"2fa code", []string{"2fa", "code"}},
+		{"user 2fa", []string{"user", "2fa"}},
+		{"user2fa", []string{"user2fa"}},
+		{"USER_2FA", []string{"USER", "2FA"}},
+		{"HTTP2Server", []string{"HTTP2", "Server"}},
+		{"  --__  ", nil},
+		{"", nil},
+	}
+
+	for _, test := range tests {
+		result := Words(test.input)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("Words(%q) = %q; expected %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestCaseConversions(t *testing.T) {
+	tests := []struct {
+		name     string
+		convert  func(string) string
+		input    string
+		expected string
+	}{
+		{"CamelCase", CamelCase, "HTTPServer", "httpServer"},
+		{"CamelCase", CamelCase, "user-id", "userId"},
+		{"PascalCase", PascalCase, "XMLHttpRequest2", "XmlHttpRequest2"},
+		{"PascalCase", PascalCase, "été brûlant", "ÉtéBrûlant"},
+		{"SnakeCase", SnakeCase, "HTTPServer", "http_server"},
+		{"SnakeCase", SnakeCase, "ÉtéBrûlant", "été_brûlant"},
+		{"ScreamingSnakeCase", ScreamingSnakeCase, "userId", "USER_ID"},
+		{"KebabCase", KebabCase, "XMLHttpRequest2", "xml-http-request2"},
+		{"TrainCase", TrainCase, "user_id", "User-Id"},
+		{"DotCase", DotCase, "HelloWorld", "hello.world"},
+		{"TitleCase", TitleCase, "hello_world-again", "Hello World Again"},
+		{"TitleCase", TitleCase, "", ""},
+		{"CamelCase", CamelCase, "a b c", "aB_C"},
+		{"PascalCase", PascalCase, "a b c", "A_B_C"},
+		{"SnakeCase", SnakeCase, "a b c", "a_b_c"},
+		{"SnakeCase", SnakeCase, "aBC", "a_bc"},
+		{"CamelCase", CamelCase, "user 2fa", "user_2fa"},
+		{"PascalCase", PascalCase, "v 1 beta", "V_1_Beta"},
+		{"CamelCase", CamelCase, "x y2 z", "xY2Z"},
+		{"CamelCase", CamelCase, "a bc", "aBc"},
+		{"SnakeCase", SnakeCase, "user2fa", "user2fa"},
+		{"KebabCase", KebabCase, "2fa code", "2fa-code"},
+		{"TitleCase", TitleCase, "2fa code", "2fa Code"},
+		{"SnakeCase", SnakeCase, "2FA_CODE", "2fa_code"},
+	}
+
+	for _, test := range tests {
+		result := test.convert(test.input)
+		if result != test.expected {
+			t.Errorf("%s(%q) = %q; expected %q", test.name, test.input, result, test.expected)
+		}
+	}
+}
+
+func TestCaseConversionsRoundTrip(t *testing.T) {
+	converters := map[string]func(string) string{
+		"CamelCase":          CamelCase,
+		"PascalCase":         PascalCase,
+		"SnakeCase":          SnakeCase,
+		"ScreamingSnakeCase": ScreamingSnakeCase,
+		"KebabCase":          KebabCase,
+		"TrainCase":          TrainCase,
+		"DotCase":            DotCase,
+		"TitleCase":          TitleCase,
+	}
+	inputs := []string{
+		"HTTPServer", "XMLHttpRequest2", "user-id", "ÉtéBrûlant", "user2Name",
+		"a b c", "x y2 z", "2fa code", "user 2fa", "v 1 beta", "東京 駅",
+	}
+
+	for _, input := range inputs {
+		for fromName, from := range converters {
+			for toName, to := range converters {
+				direct := to(input)
+				via := to(from(input))
+				if direct != via {
+					t.Errorf("%s(%s(%q)) = %q; expected %q", toName, fromName, input, via, direct)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,511 @@
+package strings
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Op is the kind of change an Edit describes
+type Op int
+
+const (
+	// OpEqual is text present in both inputs
+	OpEqual Op = iota
+	// OpDelete is text only present in the old input
+	OpDelete
+	// OpInsert is text only present in the new input
+	OpInsert
+)
+
+// String returns the unified diff prefix of the operation: " ", "-" or "+"
+func (op Op) String() string {
+	switch op {
+	case OpDelete:
+		return "-"
+	case OpInsert:
+		return "+"
+	}
+	return " "
+}
+
+// Edit is one step of a diff. OldPos and NewPos are where the text starts
+// in the old and new input: line indexes for DiffLines and byte offsets for
+// DiffChars. For an insertion OldPos is where the text goes in the old
+// input, and for a deletion NewPos is where it was removed in the new one.
+type Edit struct {
+	Op     Op
+	Text   string
+	OldPos int
+	NewPos int
+}
+
+// myers returns the shortest edit script turning a into b as one Op per
+// element, using the linear space variant of Myers' O(ND) algorithm. The
+// common prefix and suffix are trimmed first, which keeps typical diffs of
+// similar texts cheap.
+func myers[T comparable](a, b []T) []Op {
+	// One pair of diagonal vectors, sized for the whole input, is shared by
+	// every level of the recursion
+	size := 2*(len(a)+len(b)) + 3
+	d := &myersDiff[T]{
+		a:      a,
+		b:      b,
+		vf:     make([]int, size),
+		vb:     make([]int, size),
+		offset: len(a) + len(b) + 1,
+		ops:    make([]Op, 0, len(a)+len(b)),
+	}
+	d.compare(0, len(a), 0, len(b))
+	return d.ops
+}
+
+// myersDiff holds the state of Myers' algorithm, which splits the problem
+// at the middle snake of an optimal path and recurses on both halves, so
+// that memory stays O(N+M) however different the inputs are
+type myersDiff[T comparable] struct {
+	a, b   []T
+	vf, vb []int
+	offset int
+	ops    []Op
+}
+
+// compare appends the edits turning a[aLo:aHi] into b[bLo:bHi]
+func (d *myersDiff[T]) compare(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.ops = append(d.ops, OpEqual)
+		aLo++
+		bLo++
+	}
+	suffix := 0
+	for aLo < aHi && bLo < bHi && d.a[aHi-1] == d.b[bHi-1] {
+		aHi--
+		bHi--
+		suffix++
+	}
+
+	switch {
+	case aLo == aHi:
+		for ; bLo < bHi; bLo++ {
+			d.ops = append(d.ops, OpInsert)
+		}
+	case bLo == bHi:
+		for ; aLo < aHi; aLo++ {
+			d.ops = append(d.ops, OpDelete)
+		}
+	default:
+		x, y, u, v := d.middleSnake(aLo, aHi, bLo, bHi)
+		d.compare(aLo, x, bLo, y)
+		for i := x; i < u; i++ {
+			d.ops = append(d.ops, OpEqual)
+		}
+		d.compare(u, aHi, v, bHi)
+	}
+
+	for i := 0; i < suffix; i++ {
+		d.ops = append(d.ops, OpEqual)
+	}
+}
+
+// middleSnake runs the search forwards from the start and backwards from
+// the end of a[aLo:aHi] and b[bLo:bHi] until the paths overlap, and returns
+// the snake where they meet as absolute start (x, y) and end (u, v). Both
+// ranges are non-empty and differ in their first and last elements.
+func (d *myersDiff[T]) middleSnake(aLo, aHi, bLo, bHi int) (int, int, int, int) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta&1 != 0
+	vf, vb, off := d.vf, d.vb, d.offset
+	vf[off+1], vb[off+1] = 0, 0
+
+	for step := 0; step <= (n+m+1)/2; step++ {
+		// Forward paths, with x counted from aLo
+		for k := -step; k <= step; k += 2 {
+			var x int
+			if k == -step || (k != step && vf[off+k-1] < vf[off+k+1]) {
+				x = vf[off+k+1]
+			} else {
+				x = vf[off+k-1] + 1
+			}
+			y := x - k
+			x0, y0 := x, y
+			for x < n && y < m && d.a[aLo+x] == d.b[bLo+y] {
+				x++
+				y++
+			}
+			vf[off+k] = x
+			// Overlap with a backward path of the previous step, whose
+			// diagonal delta-k is counted from the end
+			if odd && delta-k >= -(step-1) && delta-k <= step-1 && x+vb[off+delta-k] >= n {
+				return aLo + x0, bLo + y0, aLo + x, bLo + y
+			}
+		}
+
+		// Backward paths, with x counted back from aHi
+		for k := -step; k <= step; k += 2 {
+			var x int
+			if k == -step || (k != step && vb[off+k-1] < vb[off+k+1]) {
+				x = vb[off+k+1]
+			} else {
+				x = vb[off+k-1] + 1
+			}
+			y := x - k
+			x0, y0 := x, y
+			for x < n && y < m && d.a[aHi-1-x] == d.b[bHi-1-y] {
+				x++
+				y++
+			}
+			vb[off+k] = x
+			if !odd && delta-k >= -step && delta-k <= step && x+vf[off+delta-k] >= n {
+				return aHi - x, bHi - y, aHi - x0, bHi - y0
+			}
+		}
+	}
+	panic("strings: diff found no middle snake")
+}
+
+// DiffLines compares two texts line by line, as returned by file.ReadLines,
+// and returns one Edit per line
+func DiffLines(a, b []string) []Edit {
+	edits := make([]Edit, 0, len(a)+len(b))
+	i, j := 0, 0
+	for _, op := range myers(a, b) {
+		switch op {
+		case OpEqual:
+			edits = append(edits, Edit{OpEqual, a[i], i, j})
+			i++
+			j++
+		case OpDelete:
+			edits = append(edits, Edit{OpDelete, a[i], i, j})
+			i++
+		case OpInsert:
+			edits = append(edits, Edit{OpInsert, b[j], i, j})
+			j++
+		}
+	}
+	return edits
+}
+
+// DiffChars compares two strings character by character. Consecutive
+// characters with the same operation are merged into a single Edit.
+func DiffChars(a, b string) []Edit {
+	ra, rb := []rune(a), []rune(b)
+
+	var edits []Edit
+	i, j := 0, 0
+	oldPos, newPos := 0, 0
+	for _, op := range myers(ra, rb) {
+		var r rune
+		switch op {
+		case OpEqual, OpDelete:
+			r = ra[i]
+		case OpInsert:
+			r = rb[j]
+		}
+		if last := len(edits) - 1; last >= 0 && edits[last].Op == op {
+			edits[last].Text += string(r)
+		} else {
+			edits = append(edits, Edit{op, string(r), oldPos, newPos})
+		}
+
+		size := utf8.RuneLen(r)
+		if op != OpInsert {
+			i++
+			oldPos += size
+		}
+		if op != OpDelete {
+			j++
+			newPos += size
+		}
+	}
+	return edits
+}
+
+// DefaultDiffContext is the number of unchanged lines shown around each
+// change in a unified diff, as diff -u does
+const DefaultDiffContext = 3
+
+// noNewline marks a final line without a line terminator in a unified diff
+const noNewline = `\ No newline at end of file`
+
+// UnifiedDiff formats the differences between two texts given as lines in
+// the unified format of diff -u, with context unchanged lines around each
+// change. It returns an empty string when the texts are equal.
+func UnifiedDiff(oldName, newName string, a, b []string, context int) string {
+	return unifiedDiff(oldName, newName, terminate(a), terminate(b), context)
+}
+
+// Patch returns a unified diff turning a into b, for use with ApplyPatch
+func Patch(a, b string) string {
+	return unifiedDiff("a", "b", splitLinesKeepEnds(a), splitLinesKeepEnds(b), DefaultDiffContext)
+}
+
+// terminate appends a newline to every line
+func terminate(lines []string) []string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = l + "\n"
+	}
+	return out
+}
+
+// splitLinesKeepEnds splits s after every newline. The last line has no
+// terminator unless s ends in a newline.
+func splitLinesKeepEnds(s string) []string {
+	var lines []string
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '\n') + 1
+		if i == 0 {
+			i = len(s)
+		}
+		lines = append(lines, s[:i])
+		s = s[i:]
+	}
+	return lines
+}
+
+func unifiedDiff(oldName, newName string, a, b []string, context int) string {
+	if context < 0 {
+		context = 0
+	}
+	edits := DiffLines(a, b)
+
+	// Each hunk covers a run of changes separated by at most twice the
+	// context, plus context lines on either side
+	var out strings.Builder
+	for start := 0; start < len(edits); {
+		for start < len(edits) && edits[start].Op == OpEqual {
+			start++
+		}
+		if start == len(edits) {
+			break
+		}
+		end := start
+		for end < len(edits) {
+			if edits[end].Op != OpEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(edits) && edits[run].Op == OpEqual {
+				run++
+			}
+			if run == len(edits) || run-end > 2*context {
+				break
+			}
+			end = run
+		}
+
+		from := maxInt(start-context, 0)
+		to := minInt(end+context, len(edits))
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+		}
+		oldCount, newCount := 0, 0
+		for _, e := range edits[from:to] {
+			if e.Op != OpInsert {
+				oldCount++
+			}
+			if e.Op != OpDelete {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n",
+			hunkRange(edits[from].OldPos, oldCount), hunkRange(edits[from].NewPos, newCount))
+		for _, e := range edits[from:to] {
+			out.WriteString(e.Op.String())
+			out.WriteString(e.Text)
+			if !strings.HasSuffix(e.Text, "\n") {
+				out.WriteString("\n" + noNewline + "\n")
+			}
+		}
+		start = to
+	}
+	return out.String()
+}
+
+// hunkRange formats the line range of a hunk header. Lines are numbered
+// from one, and an empty range names the line before it.
+func hunkRange(pos, count int) string {
+	switch count {
+	case 0:
+		return strconv.Itoa(pos) + ",0"
+	case 1:
+		return strconv.Itoa(pos + 1)
+	}
+	return strconv.Itoa(pos+1) + "," + strconv.Itoa(count)
+}
+
+// hunk is a parsed unified diff hunk. Its lines keep their terminators.
+type hunk struct {
+	oldStart int
+	oldLines []string
+	newLines []string
+	// lead and trail count the context lines at either end
+	lead, trail int
+}
+
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// parsePatch reads the hunks of a unified diff, ignoring file headers and
+// any text between hunks
+func parsePatch(patch string) ([]hunk, error) {
+	var hunks []hunk
+	lines := splitLinesKeepEnds(patch)
+	for i := 0; i < len(lines); i++ {
+		m := hunkHeader.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+		oldStart, _ := strconv.Atoi(m[1])
+		oldCount, newCount := 1, 1
+		if m[2] != "" {
+			oldCount, _ = strconv.Atoi(m[2])
+		}
+		if m[4] != "" {
+			newCount, _ = strconv.Atoi(m[4])
+		}
+
+		h := hunk{oldStart: oldStart}
+		changed := false
+		var last *string
+		for oldCount > 0 || newCount > 0 || (i+1 < len(lines) && strings.HasPrefix(lines[i+1], `\`)) {
+			i++
+			if i >= len(lines) {
+				return nil, fmt.Errorf("patch: hunk at %q is truncated", strings.TrimSpace(m[0]))
+			}
+			line := lines[i]
+			if line == "\n" {
+				// Some editors strip the space of empty context lines
+				line = " \n"
+			}
+			switch line[0] {
+			case ' ':
+				h.oldLines = append(h.oldLines, line[1:])
+				h.newLines = append(h.newLines, line[1:])
+				if changed {
+					h.trail++
+				} else {
+					h.lead++
+				}
+				oldCount--
+				newCount--
+				last = nil
+			case '-':
+				h.oldLines = append(h.oldLines, line[1:])
+				last = &h.oldLines[len(h.oldLines)-1]
+				changed, h.trail = true, 0
+				oldCount--
+			case '+':
+				h.newLines = append(h.newLines, line[1:])
+				last = &h.newLines[len(h.newLines)-1]
+				changed, h.trail = true, 0
+				newCount--
+			case '\\':
+				// The previous line has no terminator. For context lines it
+				// applies to both sides.
+				if last != nil {
+					*last = strings.TrimSuffix(*last, "\n")
+				} else if len(h.oldLines) > 0 {
+					h.oldLines[len(h.oldLines)-1] = strings.TrimSuffix(h.oldLines[len(h.oldLines)-1], "\n")
+					h.newLines[len(h.newLines)-1] = strings.TrimSuffix(h.newLines[len(h.newLines)-1], "\n")
+				}
+			default:
+				return nil, fmt.Errorf("patch: unexpected line %q in hunk", strings.TrimSuffix(line, "\n"))
+			}
+			if oldCount < 0 || newCount < 0 {
+				return nil, fmt.Errorf("patch: hunk at %q has more lines than its header", strings.TrimSpace(m[0]))
+			}
+		}
+		if !changed {
+			h.lead, h.trail = len(h.oldLines), 0
+		}
+		hunks = append(hunks, h)
+	}
+	return hunks, nil
+}
+
+// ApplyPatch applies a unified diff to s. Hunks are located near the line
+// numbers in their headers, and also found if earlier changes to s moved
+// them. fuzz is the number of context lines at each end of a hunk that may
+// be ignored when the hunk does not match exactly, as with patch --fuzz.
+// An error names the first hunk that could not be placed.
+func ApplyPatch(s, patch string, fuzz int) (string, error) {
+	hunks, err := parsePatch(patch)
+	if err != nil {
+		return "", err
+	}
+
+	src := splitLinesKeepEnds(s)
+	var out []string
+	cursor, offset := 0, 0
+	for n, h := range hunks {
+		expected := h.oldStart - 1
+		if len(h.oldLines) == 0 {
+			expected = h.oldStart
+		}
+		expected += offset
+
+		placed := false
+		for f := 0; f <= fuzz && !placed; f++ {
+			lead, trail := minInt(f, h.lead), minInt(f, h.trail)
+			if f > 0 && lead == minInt(f-1, h.lead) && trail == minInt(f-1, h.trail) {
+				// No more context left to ignore
+				break
+			}
+			old := h.oldLines[lead : len(h.oldLines)-trail]
+			pos, ok := findLines(src, old, expected+lead, cursor)
+			if !ok {
+				continue
+			}
+			out = append(out, src[cursor:pos]...)
+			out = append(out, h.newLines[lead:len(h.newLines)-trail]...)
+			cursor = pos + len(old)
+			offset = pos - lead - (h.oldStart - 1)
+			if len(h.oldLines) == 0 {
+				offset = pos - h.oldStart
+			}
+			placed = true
+		}
+		if !placed {
+			return "", fmt.Errorf("patch: hunk %d at line %d does not apply", n+1, h.oldStart)
+		}
+	}
+	out = append(out, src[cursor:]...)
+	return strings.Join(out, ""), nil
+}
+
+// findLines finds want in lines at or after min, choosing the match
+// closest to the expected position
+func findLines(lines, want []string, expected, min int) (int, bool) {
+	last := len(lines) - len(want)
+	if expected < min {
+		expected = min
+	}
+	if expected > last {
+		expected = last
+	}
+	matches := func(pos int) bool {
+		if pos < min || pos > last {
+			return false
+		}
+		for i, w := range want {
+			if lines[pos+i] != w {
+				return false
+			}
+		}
+		return true
+	}
+	for delta := 0; expected-delta >= min || expected+delta <= last; delta++ {
+		if matches(expected - delta) {
+			return expected - delta, true
+		}
+		if matches(expected + delta) {
+			return expected + delta, true
+		}
+	}
+	return 0, false
+}
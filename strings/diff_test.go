@@ -0,0 +1,199 @@
+package strings
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	a := []string{"a", "b", "c", "d"}
+	b := []string{"a", "c", "d", "e"}
+	expected := []Edit{
+		{OpEqual, "a", 0, 0},
+		{OpDelete, "b", 1, 1},
+		{OpEqual, "c", 2, 1},
+		{OpEqual, "d", 3, 2},
+		{OpInsert, "e", 4, 3},
+	}
+
+	result := DiffLines(a, b)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("DiffLines(%q, %q) = %v; expected %v", a, b, result, expected)
+	}
+}
+
+func TestDiffLinesIsMinimal(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		changes int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"abcabba", "cbabac", 5},
+		{"kitten", "sitting", 5},
+		{"same", "same", 0},
+	}
+
+	for _, test := range tests {
+		a, b := strings.Split(test.a, ""), strings.Split(test.b, "")
+		edits := DiffLines(a, b)
+		changes := 0
+		var old, new []string
+		for _, e := range edits {
+			if e.Op != OpEqual {
+				changes++
+			}
+			if e.Op != OpInsert {
+				old = append(old, e.Text)
+			}
+			if e.Op != OpDelete {
+				new = append(new, e.Text)
+			}
+		}
+		if changes != test.changes {
+			t.Errorf("DiffLines(%q, %q) made %d changes; expected %d", test.a, test.b, changes, test.changes)
+		}
+		if strings.Join(old, "") != test.a || strings.Join(new, "") != test.b {
+			t.Errorf("DiffLines(%q, %q) does not reproduce its inputs", test.a, test.b)
+		}
+	}
+}
+
+func TestDiffChars(t *testing.T) {
+	expected := []Edit{
+		{OpEqual, "caf", 0, 0},
+		{OpDelete, "e", 3, 3},
+		{OpInsert, "é", 4, 3},
+		{OpEqual, " au lait", 4, 5},
+	}
+
+	result := DiffChars("cafe au lait", "café au lait")
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("DiffChars = %v; expected %v", result, expected)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	a := []string{"one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten"}
+	b := []string{"one", "2", "three", "four", "five", "six", "seven", "eight", "nine", "ten", "eleven"}
+	expected := `--- old
++++ new
+@@ -1,5 +1,5 @@
+ one
+-two
++2
+ three
+ four
+ five
+@@ -8,3 +8,4 @@
+ eight
+ nine
+ ten
++eleven
+`
+
+	result := UnifiedDiff("old", "new", a, b, DefaultDiffContext)
+	if result != expected {
+		t.Errorf("UnifiedDiff = %q; expected %q", result, expected)
+	}
+	if UnifiedDiff("old", "new", a, a, DefaultDiffContext) != "" {
+		t.Error("UnifiedDiff of equal texts should be empty")
+	}
+}
+
+func TestPatchRoundTrip(t *testing.T) {
+	tests := []struct {
+		a, b string
+	}{
+		{"", "new file\n"},
+		{"old file\n", ""},
+		{"a\nb\nc\n", "a\nB\nc\n"},
+		{"no newline", "no newline\nadded"},
+		{"x\ny", "x\ny\n"},
+		{"1\n2\n3\n4\n5\n6\n7\n8\n9\n", "0\n1\n2\n3\n5\n6\n7\n8\n9\n10\n"},
+	}
+
+	for _, test := range tests {
+		patch := Patch(test.a, test.b)
+		result, err := ApplyPatch(test.a, patch, 0)
+		if err != nil {
+			t.Errorf("ApplyPatch(%q, %q) returned error: %v", test.a, patch, err)
+			continue
+		}
+		if result != test.b {
+			t.Errorf("ApplyPatch(%q, Patch(%q, %q)) = %q", test.a, test.a, test.b, result)
+		}
+	}
+}
+
+func TestApplyPatchOffsetAndFuzz(t *testing.T) {
+	original := "host=a\nport=1\nuser=x\nmode=dev\nlog=info\n"
+	changed := "host=a\nport=1\nuser=x\nmode=prod\nlog=info\n"
+	patch := Patch(original, changed)
+
+	// Lines added above the hunk move it down
+	shifted := "# header\n# more\n" + original
+	result, err := ApplyPatch(shifted, patch, 0)
+	if err != nil || result != "# header\n# more\n"+changed {
+		t.Errorf("ApplyPatch with offset = %q, %v", result, err)
+	}
+
+	// A changed context line needs fuzz
+	drifted := "host=b\nport=1\nuser=x\nmode=dev\nlog=info\n"
+	if _, err := ApplyPatch(drifted, patch, 0); err == nil {
+		t.Error("ApplyPatch without fuzz should reject changed context")
+	}
+	result, err = ApplyPatch(drifted, patch, 2)
+	if err != nil || result != "host=b\nport=1\nuser=x\nmode=prod\nlog=info\n" {
+		t.Errorf("ApplyPatch with fuzz = %q, %v", result, err)
+	}
+
+	// A changed line to delete never applies
+	conflict := "host=a\nport=1\nuser=x\nmode=test\nlog=info\n"
+	if _, err := ApplyPatch(conflict, patch, 3); err == nil {
+		t.Error("ApplyPatch should reject a conflicting change")
+	}
+}
+
+func TestApplyPatchErrors(t *testing.T) {
+	if _, err := ApplyPatch("a\n", "@@ -1,2 +1,2 @@\n a\n", 0); err == nil {
+		t.Error("ApplyPatch should reject a truncated hunk")
+	}
+	if _, err := ApplyPatch("a\n", "@@ -1 +1 @@\n*a\n", 0); err == nil {
+		t.Error("ApplyPatch should reject a malformed hunk line")
+	}
+}
+
+func TestDiffLinesLargeDisjointInputs(t *testing.T) {
+	// Every line differs, the worst case for the edit distance
+	a, b := make([]string, 5000), make([]string, 5000)
+	for i := range a {
+		a[i] = "old " + strconv.Itoa(i)
+		b[i] = "new " + strconv.Itoa(i)
+	}
+
+	edits := DiffLines(a, b)
+	deletes, inserts := 0, 0
+	for _, e := range edits {
+		switch e.Op {
+		case OpDelete:
+			if e.Text != a[e.OldPos] {
+				t.Fatalf("deletion of %q at %d; expected %q", e.Text, e.OldPos, a[e.OldPos])
+			}
+			deletes++
+		case OpInsert:
+			if e.Text != b[e.NewPos] {
+				t.Fatalf("insertion of %q at %d; expected %q", e.Text, e.NewPos, b[e.NewPos])
+			}
+			inserts++
+		default:
+			t.Fatalf("unexpected equal line %q", e.Text)
+		}
+	}
+	if deletes != len(a) || inserts != len(b) {
+		t.Errorf("DiffLines made %d deletions and %d insertions; expected %d and %d", deletes, inserts, len(a), len(b))
+	}
+}
@@ -0,0 +1,448 @@
+package strings
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Levenshtein returns the minimum number of single-character insertions,
+// deletions and substitutions needed to turn a into b
+func Levenshtein(a, b string) int {
+	d, _ := levenshtein([]rune(a), []rune(b), -1)
+	return d
+}
+
+// LevenshteinBounded is like Levenshtein but gives up as soon as the
+// distance is known to exceed max. It then returns max+1 and false.
+func LevenshteinBounded(a, b string, max int) (int, bool) {
+	return levenshtein([]rune(a), []rune(b), max)
+}
+
+// levenshtein computes the edit distance with two rows of the DP matrix. A
+// negative max disables the early exit.
+func levenshtein(a, b []rune, max int) (int, bool) {
+	if max >= 0 && abs(len(a)-len(b)) > max {
+		return max + 1, false
+	}
+
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if curr[j] < rowMin {
+				rowMin = curr[j]
+			}
+		}
+		if max >= 0 && rowMin > max {
+			return max + 1, false
+		}
+		prev, curr = curr, prev
+	}
+
+	d := prev[len(b)]
+	if max >= 0 && d > max {
+		return max + 1, false
+	}
+	return d, true
+}
+
+// DamerauLevenshtein returns the edit distance between a and b where a
+// transposition of two characters also counts as a single edit, even when
+// the transposed characters are edited again ("ca" -> "abc" is 2)
+func DamerauLevenshtein(a, b string) int {
+	d, _ := damerauLevenshtein([]rune(a), []rune(b), -1)
+	return d
+}
+
+// DamerauLevenshteinBounded is like DamerauLevenshtein but gives up as soon
+// as the distance is known to exceed max. It then returns max+1 and false.
+func DamerauLevenshteinBounded(a, b string, max int) (int, bool) {
+	return damerauLevenshtein([]rune(a), []rune(b), max)
+}
+
+// damerauLevenshtein implements the Lowrance-Wagner algorithm for the
+// unrestricted Damerau-Levenshtein distance. Row minima never decrease, so
+// the early exit is safe even with transpositions across rows.
+func damerauLevenshtein(a, b []rune, max int) (int, bool) {
+	if max >= 0 && abs(len(a)-len(b)) > max {
+		return max + 1, false
+	}
+
+	inf := len(a) + len(b)
+	lastRow := make(map[rune]int)
+
+	// d is offset by one in both dimensions so that row and column 0 can
+	// hold the "infinite" sentinel
+	d := make([][]int, len(a)+2)
+	for i := range d {
+		d[i] = make([]int, len(b)+2)
+	}
+	d[0][0] = inf
+	for i := 0; i <= len(a); i++ {
+		d[i+1][0] = inf
+		d[i+1][1] = i
+	}
+	for j := 0; j <= len(b); j++ {
+		d[0][j+1] = inf
+		d[1][j+1] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		lastCol := 0
+		rowMin := inf
+		for j := 1; j <= len(b); j++ {
+			k := lastRow[b[j-1]]
+			l := lastCol
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+				lastCol = j
+			}
+			d[i+1][j+1] = min3(
+				d[i][j]+cost,
+				d[i+1][j]+1,
+				d[i][j+1]+1,
+			)
+			if t := d[k][l] + (i - k - 1) + 1 + (j - l - 1); t < d[i+1][j+1] {
+				d[i+1][j+1] = t
+			}
+			if d[i+1][j+1] < rowMin {
+				rowMin = d[i+1][j+1]
+			}
+		}
+		if max >= 0 && len(b) > 0 && rowMin > max {
+			return max + 1, false
+		}
+		lastRow[a[i-1]] = i
+	}
+
+	dist := d[len(a)+1][len(b)+1]
+	if max >= 0 && dist > max {
+		return max + 1, false
+	}
+	return dist, true
+}
+
+// Jaro returns the Jaro similarity of a and b, between 0 and 1
+func Jaro(a, b string) float64 {
+	return jaro([]rune(a), []rune(b))
+}
+
+func jaro(a, b []rune) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	window := maxInt(len(a), len(b))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	aMatched := make([]bool, len(a))
+	bMatched := make([]bool, len(b))
+	matches := 0
+	for i := range a {
+		lo := maxInt(0, i-window)
+		hi := minInt(len(b), i+window+1)
+		for j := lo; j < hi; j++ {
+			if !bMatched[j] && a[i] == b[j] {
+				aMatched[i] = true
+				bMatched[j] = true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	j := 0
+	for i := range a {
+		if !aMatched[i] {
+			continue
+		}
+		for !bMatched[j] {
+			j++
+		}
+		if a[i] != b[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	return (m/float64(len(a)) + m/float64(len(b)) + (m-float64(transpositions/2))/m) / 3
+}
+
+// JaroWinkler returns the Jaro-Winkler similarity of a and b, between 0 and
+// 1. Strings sharing a prefix of up to four characters score higher.
+func JaroWinkler(a, b string) float64 {
+	return jaroWinkler([]rune(a), []rune(b))
+}
+
+// JaroWinklerAtLeast is like JaroWinkler but reports false without doing
+// the full comparison when the lengths alone rule out reaching min
+func JaroWinklerAtLeast(a, b string, min float64) (float64, bool) {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) > 0 && len(rb) > 0 {
+		// Best case: every character of the shorter string matches
+		short, long := float64(minInt(len(ra), len(rb))), float64(maxInt(len(ra), len(rb)))
+		upper := (1 + short/long + 1) / 3
+		upper += 0.4 * (1 - upper)
+		if upper < min {
+			return 0, false
+		}
+	}
+	score := jaroWinkler(ra, rb)
+	return score, score >= min
+}
+
+func jaroWinkler(a, b []rune) float64 {
+	sim := jaro(a, b)
+	if sim <= 0.7 {
+		return sim
+	}
+	prefix := 0
+	for prefix < 4 && prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	return sim + float64(prefix)*0.1*(1-sim)
+}
+
+// LongestCommonSubsequence returns the longest sequence of characters that
+// appears in both a and b in the same relative order
+func LongestCommonSubsequence(a, b string) string {
+	ra, rb := []rune(a), []rune(b)
+	table := lcsTable(ra, rb)
+
+	out := make([]rune, table[len(ra)][len(rb)])
+	i, j, k := len(ra), len(rb), len(out)
+	for i > 0 && j > 0 {
+		switch {
+		case ra[i-1] == rb[j-1]:
+			k--
+			out[k] = ra[i-1]
+			i--
+			j--
+		case table[i-1][j] >= table[i][j-1]:
+			i--
+		default:
+			j--
+		}
+	}
+	return string(out)
+}
+
+func lcsTable(a, b []rune) [][]int {
+	table := make([][]int, len(a)+1)
+	for i := range table {
+		table[i] = make([]int, len(b)+1)
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				table[i][j] = table[i-1][j-1] + 1
+			} else {
+				table[i][j] = maxInt(table[i-1][j], table[i][j-1])
+			}
+		}
+	}
+	return table
+}
+
+// LCSLength returns the length of the longest common subsequence of a and b
+func LCSLength(a, b string) int {
+	n, _ := lcsLength([]rune(a), []rune(b), -1)
+	return n
+}
+
+// LCSLengthAtLeast is like LCSLength but gives up as soon as the remaining
+// characters can no longer bring the length up to min
+func LCSLengthAtLeast(a, b string, min int) (int, bool) {
+	return lcsLength([]rune(a), []rune(b), min)
+}
+
+func lcsLength(a, b []rune, min int) (int, bool) {
+	if min > minInt(len(a), len(b)) {
+		return 0, false
+	}
+
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				curr[j] = prev[j-1] + 1
+			} else {
+				curr[j] = maxInt(prev[j], curr[j-1])
+			}
+		}
+		if min >= 0 && curr[len(b)]+len(a)-i < min {
+			return curr[len(b)], false
+		}
+		prev, curr = curr, prev
+	}
+
+	n := prev[len(b)]
+	return n, min < 0 || n >= min
+}
+
+// NGramSimilarity returns the Jaccard similarity of the sets of n-character
+// grams of a and b, between 0 and 1. Both strings are padded with n-1
+// spaces on each side so that short strings and word edges count.
+func NGramSimilarity(a, b string, n int) float64 {
+	score, _ := ngramSimilarity(a, b, n, -1)
+	return score
+}
+
+// NGramSimilarityAtLeast is like NGramSimilarity but reports false without
+// comparing the grams when the set sizes alone rule out reaching min
+func NGramSimilarityAtLeast(a, b string, n int, min float64) (float64, bool) {
+	return ngramSimilarity(a, b, n, min)
+}
+
+// TrigramSimilarity returns NGramSimilarity with n = 3
+func TrigramSimilarity(a, b string) float64 {
+	return NGramSimilarity(a, b, 3)
+}
+
+func ngramSimilarity(a, b string, n int, min float64) (float64, bool) {
+	if a == b {
+		return 1, true
+	}
+	ga, gb := ngrams(a, n), ngrams(b, n)
+	if len(ga) == 0 || len(gb) == 0 {
+		return 0, min <= 0
+	}
+
+	small, large := ga, gb
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	if min >= 0 && float64(len(small))/float64(len(large)) < min {
+		return 0, false
+	}
+
+	shared := 0
+	for g := range small {
+		if large[g] {
+			shared++
+		}
+	}
+	score := float64(shared) / float64(len(ga)+len(gb)-shared)
+	return score, score >= min
+}
+
+func ngrams(s string, n int) map[string]bool {
+	if n <= 0 {
+		return nil
+	}
+	pad := strings.Repeat(" ", n-1)
+	runes := []rune(pad + s + pad)
+	grams := make(map[string]bool)
+	for i := 0; i+n <= len(runes); i++ {
+		grams[string(runes[i:i+n])] = true
+	}
+	return grams
+}
+
+// FuzzyMatch is a candidate returned by FuzzyFind together with its score
+// and its position in the candidate list
+type FuzzyMatch struct {
+	Candidate string
+	Index     int
+	Score     float64
+}
+
+// DefaultFuzzyThreshold is the minimum score FuzzyFind reports
+const DefaultFuzzyThreshold = 0.7
+
+// FuzzyFind ranks the candidates by similarity to query, best first, and
+// returns those scoring at least DefaultFuzzyThreshold. Comparison ignores
+// case, like ContainsIgnoreCase.
+func FuzzyFind(query string, candidates []string) []FuzzyMatch {
+	return FuzzyFindMin(query, candidates, DefaultFuzzyThreshold)
+}
+
+// FuzzyFindMin is like FuzzyFind with a caller-chosen minimum score
+func FuzzyFindMin(query string, candidates []string, min float64) []FuzzyMatch {
+	q := strings.ToLower(query)
+	var matches []FuzzyMatch
+	for i, candidate := range candidates {
+		if score := fuzzyScore(q, strings.ToLower(candidate)); score >= min {
+			matches = append(matches, FuzzyMatch{Candidate: candidate, Index: i, Score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	return matches
+}
+
+// fuzzyScore combines Jaro-Winkler, which favours shared prefixes, with the
+// normalized Damerau-Levenshtein distance, which favours typos, and rewards
+// candidates that contain the query outright
+func fuzzyScore(query, candidate string) float64 {
+	if query == candidate {
+		return 1
+	}
+
+	score := JaroWinkler(query, candidate)
+
+	longest := maxInt(utf8.RuneCountInString(query), utf8.RuneCountInString(candidate))
+	if d := DamerauLevenshtein(query, candidate); longest > 0 {
+		if s := 1 - float64(d)/float64(longest); s > score {
+			score = s
+		}
+	}
+
+	if query != "" && strings.Contains(candidate, query) {
+		ratio := float64(utf8.RuneCountInString(query)) / float64(utf8.RuneCountInString(candidate))
+		if s := 0.75 + 0.25*ratio; s > score {
+			score = s
+		}
+	}
+	return score
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min3(a, b, c int) int {
+	return minInt(minInt(a, b), c)
+}
@@ -0,0 +1,187 @@
+package strings
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"same", "same", 0},
+		{"café", "cafe", 1},
+		{"ca", "ac", 2},
+	}
+
+	for _, test := range tests {
+		result := Levenshtein(test.a, test.b)
+		if result != test.expected {
+			t.Errorf("Levenshtein(%q, %q) = %d; expected %d", test.a, test.b, result, test.expected)
+		}
+	}
+}
+
+func TestLevenshteinBounded(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		max      int
+		expected int
+		ok       bool
+	}{
+		{"kitten", "sitting", 3, 3, true},
+		{"kitten", "sitting", 2, 3, false},
+		{"a", "abcdefgh", 2, 3, false},
+		{"abc", "abc", 0, 0, true},
+	}
+
+	for _, test := range tests {
+		result, ok := LevenshteinBounded(test.a, test.b, test.max)
+		if result != test.expected || ok != test.ok {
+			t.Errorf("LevenshteinBounded(%q, %q, %d) = %d, %v; expected %d, %v", test.a, test.b, test.max, result, ok, test.expected, test.ok)
+		}
+	}
+}
+
+func TestDamerauLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"ca", "ac", 1},
+		{"ca", "abc", 2},
+		{"kitten", "sitting", 3},
+		{"teh", "the", 1},
+		{"", "ab", 2},
+		{"abcdef", "badcfe", 3},
+	}
+
+	for _, test := range tests {
+		result := DamerauLevenshtein(test.a, test.b)
+		if result != test.expected {
+			t.Errorf("DamerauLevenshtein(%q, %q) = %d; expected %d", test.a, test.b, result, test.expected)
+		}
+		bounded, ok := DamerauLevenshteinBounded(test.a, test.b, test.expected)
+		if bounded != test.expected || !ok {
+			t.Errorf("DamerauLevenshteinBounded(%q, %q, %d) = %d, %v; expected %d, true", test.a, test.b, test.expected, bounded, ok, test.expected)
+		}
+		if test.expected > 0 {
+			if _, ok := DamerauLevenshteinBounded(test.a, test.b, test.expected-1); ok {
+				t.Errorf("DamerauLevenshteinBounded(%q, %q, %d) reported ok", test.a, test.b, test.expected-1)
+			}
+		}
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected float64
+	}{
+		{"MARTHA", "MARHTA", 0.9611},
+		{"DIXON", "DICKSONX", 0.8133},
+		{"DWAYNE", "DUANE", 0.84},
+		{"abc", "abc", 1},
+		{"abc", "xyz", 0},
+		{"", "", 1},
+		{"", "a", 0},
+	}
+
+	for _, test := range tests {
+		result := JaroWinkler(test.a, test.b)
+		if math.Abs(result-test.expected) > 0.0001 {
+			t.Errorf("JaroWinkler(%q, %q) = %.4f; expected %.4f", test.a, test.b, result, test.expected)
+		}
+	}
+
+	if _, ok := JaroWinklerAtLeast("a", "abcdefghij", 0.9); ok {
+		t.Error("JaroWinklerAtLeast should reject strings of very different length")
+	}
+	if score, ok := JaroWinklerAtLeast("MARTHA", "MARHTA", 0.9); !ok || math.Abs(score-0.9611) > 0.0001 {
+		t.Errorf("JaroWinklerAtLeast(MARTHA, MARHTA, 0.9) = %.4f, %v; expected 0.9611, true", score, ok)
+	}
+}
+
+func TestLongestCommonSubsequence(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected string
+	}{
+		{"ABCBDAB", "BDCABA", "BCBA"},
+		{"AGGTAB", "GXTXAYB", "GTAB"},
+		{"", "abc", ""},
+		{"añob", "xñyb", "ñb"},
+	}
+
+	for _, test := range tests {
+		result := LongestCommonSubsequence(test.a, test.b)
+		if result != test.expected {
+			t.Errorf("LongestCommonSubsequence(%q, %q) = %q; expected %q", test.a, test.b, result, test.expected)
+		}
+		if n := LCSLength(test.a, test.b); n != len([]rune(test.expected)) {
+			t.Errorf("LCSLength(%q, %q) = %d; expected %d", test.a, test.b, n, len([]rune(test.expected)))
+		}
+	}
+
+	if _, ok := LCSLengthAtLeast("AGGTAB", "GXTXAYB", 5); ok {
+		t.Error("LCSLengthAtLeast should report false when the minimum is out of reach")
+	}
+	if n, ok := LCSLengthAtLeast("AGGTAB", "GXTXAYB", 4); n != 4 || !ok {
+		t.Errorf("LCSLengthAtLeast(AGGTAB, GXTXAYB, 4) = %d, %v; expected 4, true", n, ok)
+	}
+}
+
+func TestNGramSimilarity(t *testing.T) {
+	if s := TrigramSimilarity("hello", "hello"); s != 1 {
+		t.Errorf("TrigramSimilarity of equal strings = %v; expected 1", s)
+	}
+	if s := TrigramSimilarity("abc", "xyz"); s != 0 {
+		t.Errorf("TrigramSimilarity of disjoint strings = %v; expected 0", s)
+	}
+
+	near := TrigramSimilarity("postgres", "postgre")
+	far := TrigramSimilarity("postgres", "mysql")
+	if near <= far {
+		t.Errorf("TrigramSimilarity ranked %v (near) below %v (far)", near, far)
+	}
+
+	// "ab" and "abcd" padded to bigrams: {" a", "ab", "b "} and {" a", "ab", "bc", "cd", "d "}
+	if s := NGramSimilarity("ab", "abcd", 2); math.Abs(s-2.0/6.0) > 1e-9 {
+		t.Errorf("NGramSimilarity(ab, abcd, 2) = %v; expected %v", s, 2.0/6.0)
+	}
+	if _, ok := NGramSimilarityAtLeast("ab", "abcdefghijkl", 2, 0.5); ok {
+		t.Error("NGramSimilarityAtLeast should reject strings of very different length")
+	}
+}
+
+func TestFuzzyFind(t *testing.T) {
+	commands := []string{"install", "uninstall", "list", "status", "commit", "config"}
+
+	matches := FuzzyFind("isntall", commands)
+	if len(matches) == 0 || matches[0].Candidate != "install" || matches[0].Index != 0 {
+		t.Fatalf("FuzzyFind(isntall) = %+v; expected install first", matches)
+	}
+
+	matches = FuzzyFind("STAUTS", commands)
+	if len(matches) == 0 || matches[0].Candidate != "status" {
+		t.Errorf("FuzzyFind(STAUTS) = %+v; expected status first", matches)
+	}
+
+	for i := 1; i < len(matches); i++ {
+		if matches[i].Score > matches[i-1].Score {
+			t.Errorf("FuzzyFind results not sorted: %+v", matches)
+		}
+	}
+
+	if matches := FuzzyFind("zzzzzz", commands); len(matches) != 0 {
+		t.Errorf("FuzzyFind(zzzzzz) = %+v; expected no matches", matches)
+	}
+	if matches := FuzzyFindMin("conf", commands, 0.8); len(matches) == 0 || matches[0].Candidate != "config" {
+		t.Errorf("FuzzyFindMin(conf) = %+v; expected config first", matches)
+	}
+}
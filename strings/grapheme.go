@@ -0,0 +1,386 @@
+package strings
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// graphemeProperty is the Grapheme_Cluster_Break property of a rune as
+// defined by UAX #29
+type graphemeProperty int
+
+const (
+	gbOther graphemeProperty = iota
+	gbCR
+	gbLF
+	gbControl
+	gbExtend
+	gbZWJ
+	gbRegionalIndicator
+	gbPrepend
+	gbSpacingMark
+	gbL
+	gbV
+	gbT
+	gbLV
+	gbLVT
+	gbExtendedPictographic
+)
+
+// prepend holds the Prepend characters, which attach to what follows them
+var prepend = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x0600, 0x0605, 1},
+		{0x06DD, 0x06DD, 1},
+		{0x070F, 0x070F, 1},
+		{0x0890, 0x0891, 1},
+		{0x08E2, 0x08E2, 1},
+		{0x0D4E, 0x0D4E, 1},
+	},
+	R32: []unicode.Range32{
+		{0x110BD, 0x110BD, 1},
+		{0x110CD, 0x110CD, 1},
+		{0x111C2, 0x111C3, 1},
+		{0x1193F, 0x1193F, 1},
+		{0x11941, 0x11941, 1},
+		{0x11A3A, 0x11A3A, 1},
+		{0x11A84, 0x11A89, 1},
+		{0x11D46, 0x11D46, 1},
+	},
+}
+
+// extendedPictographic approximates the Extended_Pictographic property,
+// which covers emoji and the symbols that can take emoji presentation
+var extendedPictographic = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x00A9, 0x00AE, 5},
+		{0x203C, 0x203C, 1},
+		{0x2049, 0x2049, 1},
+		{0x2122, 0x2122, 1},
+		{0x2139, 0x2139, 1},
+		{0x2194, 0x2199, 1},
+		{0x21A9, 0x21AA, 1},
+		{0x231A, 0x231B, 1},
+		{0x2328, 0x2328, 1},
+		{0x2388, 0x2388, 1},
+		{0x23CF, 0x23CF, 1},
+		{0x23E9, 0x23F3, 1},
+		{0x23F8, 0x23FA, 1},
+		{0x24C2, 0x24C2, 1},
+		{0x25AA, 0x25AB, 1},
+		{0x25B6, 0x25B6, 1},
+		{0x25C0, 0x25C0, 1},
+		{0x25FB, 0x25FE, 1},
+		{0x2600, 0x27BF, 1},
+		{0x2934, 0x2935, 1},
+		{0x2B05, 0x2B07, 1},
+		{0x2B1B, 0x2B1C, 1},
+		{0x2B50, 0x2B50, 1},
+		{0x2B55, 0x2B55, 1},
+		{0x3030, 0x3030, 1},
+		{0x303D, 0x303D, 1},
+		{0x3297, 0x3297, 1},
+		{0x3299, 0x3299, 1},
+	},
+	R32: []unicode.Range32{
+		{0x1F000, 0x1F1E5, 1},
+		{0x1F200, 0x1F3FA, 1},
+		{0x1F400, 0x1FAFF, 1},
+		{0x1FC00, 0x1FFFD, 1},
+	},
+}
+
+// graphemePropertyOf classifies a rune for grapheme cluster segmentation
+func graphemePropertyOf(r rune) graphemeProperty {
+	switch {
+	case r == '\r':
+		return gbCR
+	case r == '\n':
+		return gbLF
+	case r == '\u200D':
+		return gbZWJ
+	case r == '\u200C', r >= 0x1F3FB && r <= 0x1F3FF, r >= 0xE0020 && r <= 0xE007F:
+		// ZWNJ, emoji skin tone modifiers and emoji tag characters
+		return gbExtend
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return gbRegionalIndicator
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return gbL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return gbV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return gbT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return gbLV
+		}
+		return gbLVT
+	case unicode.In(r, prepend):
+		return gbPrepend
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return gbExtend
+	case unicode.Is(unicode.Mc, r):
+		return gbSpacingMark
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp):
+		return gbControl
+	case unicode.Is(extendedPictographic, r):
+		return gbExtendedPictographic
+	}
+	return gbOther
+}
+
+// firstGrapheme returns the length in bytes of the first extended grapheme
+// cluster in s, following the boundary rules of UAX #29
+func firstGrapheme(s string) int {
+	if s == "" {
+		return 0
+	}
+
+	r, size := utf8.DecodeRuneInString(s)
+	prev := graphemePropertyOf(r)
+	// Whether the cluster so far matches ExtPict Extend* (for GB11) and how
+	// many regional indicators it contains (for GB12)
+	pictographic := prev == gbExtendedPictographic
+	regional := 0
+	if prev == gbRegionalIndicator {
+		regional = 1
+	}
+
+	pos := size
+	for pos < len(s) {
+		r, size = utf8.DecodeRuneInString(s[pos:])
+		next := graphemePropertyOf(r)
+
+		if !graphemeJoins(prev, next, pictographic, regional) {
+			break
+		}
+
+		switch next {
+		case gbRegionalIndicator:
+			regional++
+		case gbZWJ:
+		case gbExtend:
+			if prev == gbZWJ {
+				pictographic = false
+			}
+		default:
+			pictographic = next == gbExtendedPictographic
+		}
+
+		prev = next
+		pos += size
+	}
+	return pos
+}
+
+// graphemeJoins reports whether there is no cluster boundary between two
+// adjacent runes with the given properties
+func graphemeJoins(prev, next graphemeProperty, pictographic bool, regional int) bool {
+	switch {
+	case prev == gbCR && next == gbLF: // GB3
+		return true
+	case prev == gbCR || prev == gbLF || prev == gbControl: // GB4
+		return false
+	case next == gbCR || next == gbLF || next == gbControl: // GB5
+		return false
+	case prev == gbL && (next == gbL || next == gbV || next == gbLV || next == gbLVT): // GB6
+		return true
+	case (prev == gbLV || prev == gbV) && (next == gbV || next == gbT): // GB7
+		return true
+	case (prev == gbLVT || prev == gbT) && next == gbT: // GB8
+		return true
+	case next == gbExtend || next == gbZWJ || next == gbSpacingMark: // GB9, GB9a
+		return true
+	case prev == gbPrepend: // GB9b
+		return true
+	case prev == gbZWJ && next == gbExtendedPictographic && pictographic: // GB11
+		return true
+	case prev == gbRegionalIndicator && next == gbRegionalIndicator: // GB12, GB13
+		return regional%2 == 1
+	}
+	return false // GB999
+}
+
+// Graphemes splits a string into user-perceived characters (extended
+// grapheme clusters), so that "é", a flag or an emoji ZWJ sequence
+// each come back as a single element
+func Graphemes(s string) []string {
+	var clusters []string
+	for s != "" {
+		n := firstGrapheme(s)
+		clusters = append(clusters, s[:n])
+		s = s[n:]
+	}
+	return clusters
+}
+
+// GraphemeLength returns the number of user-perceived characters in a string
+func GraphemeLength(s string) int {
+	count := 0
+	for s != "" {
+		s = s[firstGrapheme(s):]
+		count++
+	}
+	return count
+}
+
+// DisplayWidth returns the number of terminal columns a string occupies.
+// East Asian wide and fullwidth characters and emoji take two columns,
+// combining marks and control characters take none.
+func DisplayWidth(s string) int {
+	width := 0
+	for s != "" {
+		n := firstGrapheme(s)
+		width += graphemeWidth(s[:n])
+		s = s[n:]
+	}
+	return width
+}
+
+// graphemeWidth returns the display width of a single grapheme cluster,
+// which is the width of its first visible rune
+func graphemeWidth(cluster string) int {
+	for _, r := range cluster {
+		if r == '\uFE0F' {
+			// An emoji presentation selector widens the whole cluster
+			return 2
+		}
+	}
+	for _, r := range cluster {
+		if w := runeWidth(r); w > 0 {
+			return w
+		}
+	}
+	return 0
+}
+
+// wide holds the East Asian Wide and Fullwidth ranges along with emoji that
+// default to emoji presentation
+var wide = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x1100, 0x115F, 1},
+		{0x231A, 0x231B, 1},
+		{0x2329, 0x232A, 1},
+		{0x23E9, 0x23EC, 1},
+		{0x23F0, 0x23F0, 1},
+		{0x23F3, 0x23F3, 1},
+		{0x25FD, 0x25FE, 1},
+		{0x2614, 0x2615, 1},
+		{0x2648, 0x2653, 1},
+		{0x267F, 0x267F, 1},
+		{0x2693, 0x2693, 1},
+		{0x26A1, 0x26A1, 1},
+		{0x26AA, 0x26AB, 1},
+		{0x26BD, 0x26BE, 1},
+		{0x26C4, 0x26C5, 1},
+		{0x26CE, 0x26CE, 1},
+		{0x26D4, 0x26D4, 1},
+		{0x26EA, 0x26EA, 1},
+		{0x26F2, 0x26F3, 1},
+		{0x26F5, 0x26F5, 1},
+		{0x26FA, 0x26FA, 1},
+		{0x26FD, 0x26FD, 1},
+		{0x2705, 0x2705, 1},
+		{0x270A, 0x270B, 1},
+		{0x2728, 0x2728, 1},
+		{0x274C, 0x274C, 1},
+		{0x274E, 0x274E, 1},
+		{0x2753, 0x2755, 1},
+		{0x2757, 0x2757, 1},
+		{0x2795, 0x2797, 1},
+		{0x27B0, 0x27B0, 1},
+		{0x27BF, 0x27BF, 1},
+		{0x2B1B, 0x2B1C, 1},
+		{0x2B50, 0x2B50, 1},
+		{0x2B55, 0x2B55, 1},
+		{0x2E80, 0x303E, 1},
+		{0x3041, 0x33FF, 1},
+		{0x3400, 0x4DBF, 1},
+		{0x4E00, 0x9FFF, 1},
+		{0xA000, 0xA4CF, 1},
+		{0xA960, 0xA97F, 1},
+		{0xAC00, 0xD7A3, 1},
+		{0xF900, 0xFAFF, 1},
+		{0xFE10, 0xFE19, 1},
+		{0xFE30, 0xFE6F, 1},
+		{0xFF00, 0xFF60, 1},
+		{0xFFE0, 0xFFE6, 1},
+	},
+	R32: []unicode.Range32{
+		{0x16FE0, 0x16FE4, 1},
+		{0x17000, 0x18CFF, 1},
+		{0x1B000, 0x1B2FF, 1},
+		{0x1F004, 0x1F004, 1},
+		{0x1F0CF, 0x1F0CF, 1},
+		{0x1F18E, 0x1F18E, 1},
+		{0x1F191, 0x1F19A, 1},
+		{0x1F1E6, 0x1F1FF, 1},
+		{0x1F200, 0x1F2FF, 1},
+		{0x1F300, 0x1F64F, 1},
+		{0x1F680, 0x1F6FF, 1},
+		{0x1F7E0, 0x1F7EB, 1},
+		{0x1F90C, 0x1F9FF, 1},
+		{0x1FA70, 0x1FAFF, 1},
+		{0x20000, 0x2FFFD, 1},
+		{0x30000, 0x3FFFD, 1},
+	},
+}
+
+// runeWidth returns the display width of a single rune
+func runeWidth(r rune) int {
+	switch {
+	case r == 0:
+		return 0
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Mn, unicode.Me, unicode.Zl, unicode.Zp):
+		return 0
+	case r >= 0x1160 && r <= 0x11FF:
+		// Hangul medial vowels and final consonants combine with the
+		// preceding initial consonant
+		return 0
+	case unicode.Is(wide, r):
+		return 2
+	}
+	return 1
+}
+
+// reverseGraphemes reverses the grapheme clusters of s
+func reverseGraphemes(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	clusters := Graphemes(s)
+	for i := len(clusters) - 1; i >= 0; i-- {
+		b.WriteString(clusters[i])
+	}
+	return b.String()
+}
+
+// TruncateWidth shortens a string so that it, including the suffix, fits in
+// the given number of terminal columns. Grapheme clusters are never split
+// and a wide character that would straddle the limit is dropped.
+func TruncateWidth(s string, width int, suffix string) string {
+	if width < 0 {
+		width = 0
+	}
+	if DisplayWidth(s) <= width {
+		return s
+	}
+
+	limit := width - DisplayWidth(suffix)
+	if limit < 0 {
+		return TruncateWidth(suffix, width, "")
+	}
+
+	used := 0
+	end := 0
+	for end < len(s) {
+		n := firstGrapheme(s[end:])
+		w := graphemeWidth(s[end : end+n])
+		if used+w > limit {
+			break
+		}
+		used += w
+		end += n
+	}
+	return s[:end] + suffix
+}
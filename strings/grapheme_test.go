@@ -0,0 +1,141 @@
+package strings
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGraphemes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"abc", []string{"a", "b", "c"}},
+		{"éa", []string{"é", "a"}},
+		{"\r\n\n", []string{"\r\n", "\n"}},
+		{"🇩🇪🇫🇷", []string{"🇩🇪", "🇫🇷"}},
+		{"🇩🇪🇫", []string{"🇩🇪", "🇫"}},
+		{"👨‍👩‍👧x", []string{"👨‍👩‍👧", "x"}},
+		{"👍🏽!", []string{"👍🏽", "!"}},
+		{"a‍👍", []string{"a‍", "👍"}},
+		{"각가", []string{"각", "가"}},
+		{"", nil},
+	}
+
+	for _, test := range tests {
+		result := Graphemes(test.input)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("Graphemes(%q) = %q; expected %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestGraphemeLength(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{"hello", 5},
+		{"café", 4},
+		{"👨‍👩‍👧", 1},
+		{"🇯🇵🇺🇸", 2},
+		{"", 0},
+	}
+
+	for _, test := range tests {
+		result := GraphemeLength(test.input)
+		if result != test.expected {
+			t.Errorf("GraphemeLength(%q) = %d; expected %d", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{"hello", 5},
+		{"日本語", 6},
+		{"ｈｉ", 4},
+		{"café", 4},
+		{"👍🏽", 2},
+		{"❤️", 2},
+		{"한국", 4},
+		{"a\tb", 2},
+		{"", 0},
+	}
+
+	for _, test := range tests {
+		result := DisplayWidth(test.input)
+		if result != test.expected {
+			t.Errorf("DisplayWidth(%q) = %d; expected %d", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestReverseGraphemes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"café", "éfac"},
+		{"🇩🇪🇫🇷", "🇫🇷🇩🇪"},
+		{"a👨‍👩‍👧b", "b👨‍👩‍👧a"},
+	}
+
+	for _, test := range tests {
+		result := Reverse(test.input)
+		if result != test.expected {
+			t.Errorf("Reverse(%q) = %q; expected %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestTruncateGraphemes(t *testing.T) {
+	tests := []struct {
+		input    string
+		length   int
+		suffix   string
+		expected string
+	}{
+		{"héllo wörld", 6, "…", "héllo…"},
+		{"café au lait", 4, "", "café"},
+		{"🇩🇪🇫🇷🇯🇵", 2, "", "🇩🇪🇫🇷"},
+		{"hello world", 2, "...", ".."},
+		{"hello world", -1, "...", ""},
+		{"日本語", 3, "...", "日本語"},
+	}
+
+	for _, test := range tests {
+		result := Truncate(test.input, test.length, test.suffix)
+		if result != test.expected {
+			t.Errorf("Truncate(%q, %d, %q) = %q; expected %q", test.input, test.length, test.suffix, result, test.expected)
+		}
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		input    string
+		width    int
+		suffix   string
+		expected string
+	}{
+		{"hello world", 8, "...", "hello..."},
+		{"hello", 8, "...", "hello"},
+		{"日本語テキスト", 7, "…", "日本語…"},
+		{"日本語テキスト", 6, "", "日本語"},
+		{"日本語テキスト", 5, "", "日本"},
+		{"ab👍🏽cd", 3, "", "ab"},
+		{"hello", 2, "...", ".."},
+		{"hello", -3, "", ""},
+	}
+
+	for _, test := range tests {
+		result := TruncateWidth(test.input, test.width, test.suffix)
+		if result != test.expected {
+			t.Errorf("TruncateWidth(%q, %d, %q) = %q; expected %q", test.input, test.width, test.suffix, result, test.expected)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+package strings
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// inflectionRule rewrites the end of a word with a regular expression
+type inflectionRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// Inflector converts English words between singular and plural forms.
+// Rules added later take precedence over earlier ones, so registered rules
+// override the defaults. An Inflector is safe for concurrent use.
+type Inflector struct {
+	mu           sync.RWMutex
+	plurals      []inflectionRule
+	singulars    []inflectionRule
+	irregular    map[string]string // singular -> plural
+	irregularRev map[string]string // plural -> singular
+	uncountable  map[string]bool
+}
+
+// DefaultInflector backs the package level Pluralize and Singularize.
+// Rules registered on it apply everywhere those functions are used.
+var DefaultInflector = NewInflector()
+
+// NewInflector returns an Inflector loaded with the English rules,
+// irregular words and uncountable words
+func NewInflector() *Inflector {
+	in := &Inflector{
+		irregular:    make(map[string]string),
+		irregularRev: make(map[string]string),
+		uncountable:  make(map[string]bool),
+	}
+
+	for _, r := range [][2]string{
+		{`$`, `s`},
+		{`s$`, `s`},
+		{`^(ax|test)is$`, `${1}es`},
+		{`(octop|vir)us$`, `${1}i`},
+		{`(octop|vir)i$`, `${1}i`},
+		{`(alias|status)$`, `${1}es`},
+		{`(bu)s$`, `${1}ses`},
+		{`(buffal|tomat)o$`, `${1}oes`},
+		{`([ti])um$`, `${1}a`},
+		{`([ti])a$`, `${1}a`},
+		{`sis$`, `ses`},
+		{`(?:([^f])fe|([lr])f)$`, `${1}${2}ves`},
+		{`(hive)$`, `${1}s`},
+		{`([^aeiouy]|qu)y$`, `${1}ies`},
+		{`(x|ch|ss|sh)$`, `${1}es`},
+		{`(matr|vert|ind)(?:ix|ex)$`, `${1}ices`},
+		{`^(m|l)ouse$`, `${1}ice`},
+		{`^(m|l)ice$`, `${1}ice`},
+		{`^(ox)$`, `${1}en`},
+		{`^(oxen)$`, `${1}`},
+		{`(quiz)$`, `${1}zes`},
+	} {
+		in.mustAdd(&in.plurals, r[0], r[1])
+	}
+
+	for _, r := range [][2]string{
+		{`s$`, ``},
+		{`(ss)$`, `${1}`},
+		{`(n)ews$`, `${1}ews`},
+		{`([ti])a$`, `${1}um`},
+		{`((a)naly|(b)a|(d)iagno|(p)arenthe|(p)rogno|(s)ynop|(t)he)(sis|ses)$`, `${1}sis`},
+		{`(^analy)(sis|ses)$`, `${1}sis`},
+		{`([^f])ves$`, `${1}fe`},
+		{`(hive)s$`, `${1}`},
+		{`(tive)s$`, `${1}`},
+		{`([lr])ves$`, `${1}f`},
+		{`([^aeiouy]|qu)ies$`, `${1}y`},
+		{`(s)eries$`, `${1}eries`},
+		{`(m)ovies$`, `${1}ovie`},
+		{`(x|ch|ss|sh)es$`, `${1}`},
+		{`^(m|l)ice$`, `${1}ouse`},
+		{`(bus)(es)?$`, `${1}`},
+		{`(o)es$`, `${1}`},
+		{`(shoe)s$`, `${1}`},
+		{`(cris|test)(is|es)$`, `${1}is`},
+		{`^(a)x[ie]s$`, `${1}xis`},
+		{`(octop|vir)(us|i)$`, `${1}us`},
+		{`(alias|status)(es)?$`, `${1}`},
+		{`^(ox)en`, `${1}`},
+		{`(vert|ind)ices$`, `${1}ex`},
+		{`(matr)ices$`, `${1}ix`},
+		{`(quiz)zes$`, `${1}`},
+		{`(database)s$`, `${1}`},
+	} {
+		in.mustAdd(&in.singulars, r[0], r[1])
+	}
+
+	for _, pair := range [][2]string{
+		{"person", "people"},
+		{"man", "men"},
+		{"woman", "women"},
+		{"child", "children"},
+		{"sex", "sexes"},
+		{"move", "moves"},
+		{"zombie", "zombies"},
+		{"goose", "geese"},
+		{"foot", "feet"},
+		{"tooth", "teeth"},
+	} {
+		in.AddIrregular(pair[0], pair[1])
+	}
+
+	in.AddUncountable("equipment", "information", "rice", "money", "species",
+		"series", "fish", "sheep", "jeans", "police", "news", "deer", "data")
+
+	return in
+}
+
+func (in *Inflector) mustAdd(rules *[]inflectionRule, pattern, replacement string) {
+	*rules = append(*rules, inflectionRule{regexp.MustCompile(`(?i)` + pattern), replacement})
+}
+
+// AddPlural registers a pluralization rule. The pattern is matched case
+// insensitively and the replacement may refer to groups as ${1}.
+func (in *Inflector) AddPlural(pattern, replacement string) error {
+	re, err := regexp.Compile(`(?i)` + pattern)
+	if err != nil {
+		return fmt.Errorf("invalid plural rule: %w", err)
+	}
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.plurals = append(in.plurals, inflectionRule{re, replacement})
+	return nil
+}
+
+// AddSingular registers a singularization rule, as AddPlural does
+func (in *Inflector) AddSingular(pattern, replacement string) error {
+	re, err := regexp.Compile(`(?i)` + pattern)
+	if err != nil {
+		return fmt.Errorf("invalid singular rule: %w", err)
+	}
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.singulars = append(in.singulars, inflectionRule{re, replacement})
+	return nil
+}
+
+// AddIrregular registers a word whose plural does not follow any rule
+func (in *Inflector) AddIrregular(singular, plural string) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	singular, plural = strings.ToLower(singular), strings.ToLower(plural)
+	delete(in.uncountable, singular)
+	delete(in.uncountable, plural)
+	in.irregular[singular] = plural
+	in.irregularRev[plural] = singular
+}
+
+// AddUncountable registers words that have no separate plural form
+func (in *Inflector) AddUncountable(words ...string) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	for _, w := range words {
+		in.uncountable[strings.ToLower(w)] = true
+	}
+}
+
+// Pluralize returns the plural form of word. Words that are already plural
+// are returned unchanged. In a phrase or a compound identifier such as
+// "BigMouse" only the last word is inflected.
+func (in *Inflector) Pluralize(word string) string {
+	return in.inflect(word, true)
+}
+
+// Singularize returns the singular form of word, as Pluralize does
+func (in *Inflector) Singularize(word string) string {
+	return in.inflect(word, false)
+}
+
+// inflect pluralizes or singularizes word. The rules are read under the
+// lock, since AddPlural and AddSingular may append to them concurrently.
+func (in *Inflector) inflect(word string, plural bool) string {
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+
+	rules, irregular, target := in.singulars, in.irregularRev, in.irregular
+	if plural {
+		rules, irregular, target = in.plurals, in.irregular, in.irregularRev
+	}
+
+	// Only the last word of a phrase or compound identifier is inflected
+	head, last := splitLastWord(word)
+	lower := strings.ToLower(last)
+	if lower == "" || in.uncountable[lower] {
+		return word
+	}
+
+	if form, ok := irregular[lower]; ok {
+		return head + matchCase(last, form)
+	}
+	if _, ok := target[lower]; ok {
+		return word
+	}
+
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rules[i].pattern.MatchString(last) {
+			return head + matchCase(last, rules[i].pattern.ReplaceAllString(last, rules[i].replacement))
+		}
+	}
+	return word
+}
+
+// splitLastWord splits word before its last word: after the last
+// non-letter, or at the last capital that follows a lower case letter, so
+// "big mouse", "big_mouse" and "BigMouse" all end in "mouse"
+func splitLastWord(word string) (string, string) {
+	start := strings.LastIndexFunc(word, func(r rune) bool { return !unicode.IsLetter(r) }) + 1
+	split, prevLower := start, false
+	for i, r := range word[start:] {
+		if unicode.IsUpper(r) && prevLower {
+			split = start + i
+		}
+		prevLower = unicode.IsLower(r)
+	}
+	return word[:split], word[split:]
+}
+
+// matchCase makes inflected follow the casing of original: all upper case
+// or a capitalized first letter carry over
+func matchCase(original, inflected string) string {
+	if original == strings.ToUpper(original) && original != strings.ToLower(original) {
+		return strings.ToUpper(inflected)
+	}
+	if first := []rune(original); len(first) > 0 && unicode.IsUpper(first[0]) {
+		return upperFirst(inflected)
+	}
+	return inflected
+}
+
+// Pluralize returns the plural form of word using DefaultInflector
+func Pluralize(word string) string {
+	return DefaultInflector.Pluralize(word)
+}
+
+// Singularize returns the singular form of word using DefaultInflector
+func Singularize(word string) string {
+	return DefaultInflector.Singularize(word)
+}
+
+// PluralizeCount formats count followed by singular, pluralized unless
+// count is exactly one: "1 minute", "5 minutes"
+func PluralizeCount(count int, singular string) string {
+	if count == 1 || count == -1 {
+		return strconv.Itoa(count) + " " + singular
+	}
+	return strconv.Itoa(count) + " " + Pluralize(singular)
+}
+
+// Ordinal returns the English ordinal suffix for n: "st", "nd", "rd" or "th"
+func Ordinal(n int) string {
+	if n < 0 {
+		n = -n
+	}
+	if n%100 >= 11 && n%100 <= 13 {
+		return "th"
+	}
+	switch n % 10 {
+	case 1:
+		return "st"
+	case 2:
+		return "nd"
+	case 3:
+		return "rd"
+	}
+	return "th"
+}
+
+// Ordinalize formats n with its ordinal suffix: "1st", "22nd", "113th"
+func Ordinalize(n int) string {
+	return strconv.Itoa(n) + Ordinal(n)
+}
+
+var (
+	smallNumbers = []string{
+		"zero", "one", "two", "three", "four", "five", "six", "seven", "eight",
+		"nine", "ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen",
+		"sixteen", "seventeen", "eighteen", "nineteen",
+	}
+	tens = []string{
+		"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy",
+		"eighty", "ninety",
+	}
+	scales = []string{
+		"", "thousand", "million", "billion", "trillion", "quadrillion",
+		"quintillion",
+	}
+)
+
+// NumberToWords spells out n in English: 123 becomes
+// "one hundred twenty-three" and -7 becomes "minus seven"
+func NumberToWords(n int64) string {
+	if n == 0 {
+		return smallNumbers[0]
+	}
+
+	// Work on the magnitude as unsigned so the minimum int64 is covered
+	u := uint64(n)
+	prefix := ""
+	if n < 0 {
+		u = uint64(-(n + 1)) + 1
+		prefix = "minus "
+	}
+
+	var groups []string
+	for scale := 0; u > 0; scale++ {
+		if chunk := int(u % 1000); chunk > 0 {
+			words := hundredsToWords(chunk)
+			if scales[scale] != "" {
+				words += " " + scales[scale]
+			}
+			groups = append([]string{words}, groups...)
+		}
+		u /= 1000
+	}
+	return prefix + strings.Join(groups, " ")
+}
+
+// hundredsToWords spells out 1 <= n <= 999
+func hundredsToWords(n int) string {
+	var parts []string
+	if n >= 100 {
+		parts = append(parts, smallNumbers[n/100]+" hundred")
+		n %= 100
+	}
+	switch {
+	case n >= 20:
+		word := tens[n/10]
+		if n%10 > 0 {
+			word += "-" + smallNumbers[n%10]
+		}
+		parts = append(parts, word)
+	case n > 0:
+		parts = append(parts, smallNumbers[n])
+	}
+	return strings.Join(parts, " ")
+}
+
+// Humanize turns an identifier into a readable phrase with a capital first
+// letter, dropping a trailing "_id": "author_id" becomes "Author" and
+// "employeeSalary" becomes "Employee salary"
+func Humanize(s string) string {
+	if strings.HasSuffix(strings.ToLower(s), "_id") {
+		s = s[:len(s)-3]
+	}
+	return upperFirst(strings.ToLower(strings.Join(Words(s), " ")))
+}
+
+// Underscore converts an identifier to snake_case, as SnakeCase does
+func Underscore(s string) string {
+	return SnakeCase(s)
+}
+
+// Tableize converts a type name to a table name: the snake_case plural,
+// so "RawScaledScorer" becomes "raw_scaled_scorers"
+func Tableize(s string) string {
+	return Pluralize(SnakeCase(s))
+}
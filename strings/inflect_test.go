@@ -0,0 +1,244 @@
+package strings
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestPluralize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"cat", "cats"},
+		{"box", "boxes"},
+		{"city", "cities"},
+		{"day", "days"},
+		{"knife", "knives"},
+		{"half", "halves"},
+		{"analysis", "analyses"},
+		{"matrix", "matrices"},
+		{"octopus", "octopi"},
+		{"status", "statuses"},
+		{"person", "people"},
+		{"Person", "People"},
+		{"PERSON", "PEOPLE"},
+		{"child", "children"},
+		{"sheep", "sheep"},
+		{"people", "people"},
+		{"cats", "cats"},
+		{"blue_box", "blue_boxes"},
+		{"sales person", "sales people"},
+		{"big mouse", "big mice"},
+		{"musk ox", "musk oxen"},
+		{"test axis", "test axes"},
+		{"Big Mouse", "Big Mice"},
+		{"BIG MOUSE", "BIG MICE"},
+		{"BigMouse", "BigMice"},
+		{"userPerson", "userPeople"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		result := Pluralize(test.input)
+		if result != test.expected {
+			t.Errorf("Pluralize(%q) = %q; expected %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestSingularize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"cats", "cat"},
+		{"boxes", "box"},
+		{"cities", "city"},
+		{"knives", "knife"},
+		{"analyses", "analysis"},
+		{"matrices", "matrix"},
+		{"statuses", "status"},
+		{"status", "status"},
+		{"people", "person"},
+		{"Children", "Child"},
+		{"news", "news"},
+		{"movies", "movie"},
+		{"databases", "database"},
+		{"person", "person"},
+		{"test axes", "test axis"},
+		{"test axis", "test axis"},
+		{"big mice", "big mouse"},
+		{"musk oxen", "musk ox"},
+		{"BigMice", "BigMouse"},
+	}
+
+	for _, test := range tests {
+		result := Singularize(test.input)
+		if result != test.expected {
+			t.Errorf("Singularize(%q) = %q; expected %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestInflectorCustomRules(t *testing.T) {
+	in := NewInflector()
+	in.AddIrregular("cactus", "cacti")
+	in.AddUncountable("feedback")
+	if err := in.AddPlural(`(corp)us$`, `${1}ora`); err != nil {
+		t.Fatalf("AddPlural returned error: %v", err)
+	}
+	if err := in.AddSingular(`(corp)ora$`, `${1}us`); err != nil {
+		t.Fatalf("AddSingular returned error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		result   string
+		expected string
+	}{
+		{"Pluralize(cactus)", in.Pluralize("cactus"), "cacti"},
+		{"Singularize(cacti)", in.Singularize("cacti"), "cactus"},
+		{"Pluralize(feedback)", in.Pluralize("feedback"), "feedback"},
+		{"Pluralize(corpus)", in.Pluralize("corpus"), "corpora"},
+		{"Singularize(corpora)", in.Singularize("corpora"), "corpus"},
+		{"default Pluralize(feedback)", Pluralize("feedback"), "feedbacks"},
+	}
+
+	for _, test := range tests {
+		if test.result != test.expected {
+			t.Errorf("%s = %q; expected %q", test.name, test.result, test.expected)
+		}
+	}
+
+	if err := in.AddPlural(`(`, ``); err == nil {
+		t.Error("AddPlural should reject an invalid pattern")
+	}
+}
+
+func TestInflectorConcurrentUse(t *testing.T) {
+	// Run with -race: adding rules must not race with inflecting
+	in := NewInflector()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				in.AddPlural(`(octop)us$`, `${1}i`)
+				in.AddSingular(`(octop)i$`, `${1}us`)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				in.Pluralize("octopus")
+				in.Singularize("octopi")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if result := in.Pluralize("octopus"); result != "octopi" {
+		t.Errorf("Pluralize(octopus) = %q; expected %q", result, "octopi")
+	}
+}
+
+func TestPluralizeCount(t *testing.T) {
+	tests := []struct {
+		count    int
+		word     string
+		expected string
+	}{
+		{0, "minute", "0 minutes"},
+		{1, "minute", "1 minute"},
+		{2, "person", "2 people"},
+	}
+
+	for _, test := range tests {
+		result := PluralizeCount(test.count, test.word)
+		if result != test.expected {
+			t.Errorf("PluralizeCount(%d, %q) = %q; expected %q", test.count, test.word, result, test.expected)
+		}
+	}
+}
+
+func TestOrdinalize(t *testing.T) {
+	tests := []struct {
+		input    int
+		expected string
+	}{
+		{1, "1st"},
+		{2, "2nd"},
+		{3, "3rd"},
+		{4, "4th"},
+		{11, "11th"},
+		{12, "12th"},
+		{13, "13th"},
+		{22, "22nd"},
+		{101, "101st"},
+		{111, "111th"},
+		{0, "0th"},
+		{-1, "-1st"},
+	}
+
+	for _, test := range tests {
+		result := Ordinalize(test.input)
+		if result != test.expected {
+			t.Errorf("Ordinalize(%d) = %q; expected %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestNumberToWords(t *testing.T) {
+	tests := []struct {
+		input    int64
+		expected string
+	}{
+		{0, "zero"},
+		{7, "seven"},
+		{15, "fifteen"},
+		{40, "forty"},
+		{123, "one hundred twenty-three"},
+		{1005, "one thousand five"},
+		{1000000, "one million"},
+		{-42, "minus forty-two"},
+		{2147483647, "two billion one hundred forty-seven million four hundred eighty-three thousand six hundred forty-seven"},
+		{math.MinInt64, "minus nine quintillion two hundred twenty-three quadrillion three hundred seventy-two trillion thirty-six billion eight hundred fifty-four million seven hundred seventy-five thousand eight hundred eight"},
+	}
+
+	for _, test := range tests {
+		result := NumberToWords(test.input)
+		if result != test.expected {
+			t.Errorf("NumberToWords(%d) = %q; expected %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestHumanizeUnderscoreTableize(t *testing.T) {
+	tests := []struct {
+		name     string
+		convert  func(string) string
+		input    string
+		expected string
+	}{
+		{"Humanize", Humanize, "employee_salary", "Employee salary"},
+		{"Humanize", Humanize, "author_id", "Author"},
+		{"Humanize", Humanize, "XMLHttpRequest", "Xml http request"},
+		{"Underscore", Underscore, "ActiveModel", "active_model"},
+		{"Tableize", Tableize, "RawScaledScorer", "raw_scaled_scorers"},
+		{"Tableize", Tableize, "Person", "people"},
+		{"Tableize", Tableize, "Category", "categories"},
+		{"Tableize", Tableize, "BigMouse", "big_mice"},
+		{"Tableize", Tableize, "MuskOx", "musk_oxen"},
+		{"Tableize", Tableize, "UserPerson", "user_people"},
+	}
+
+	for _, test := range tests {
+		result := test.convert(test.input)
+		if result != test.expected {
+			t.Errorf("%s(%q) = %q; expected %q", test.name, test.input, result, test.expected)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package strings
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/yourusername/goutils/convert"
+)
+
+// Formatter turns a placeholder value into text
+type Formatter func(value interface{}) string
+
+// Interpolate expands ${...} placeholders in template using values.
+//
+// The supported forms are:
+//
+//	${name}             the value of name, or "" when unset
+//	${name:-default}    default when name is unset or empty
+//	${name:?message}    an error carrying message when name is unset or empty
+//	${user.email}       a lookup through nested maps
+//	$${name}            a literal "${name}"
+//
+// Defaults may themselves contain placeholders. Values are formatted with
+// convert.ToString; use InterpolateWith for a different Formatter.
+func Interpolate(template string, values map[string]interface{}) (string, error) {
+	return InterpolateWith(template, values, convert.ToString)
+}
+
+// InterpolateWith is like Interpolate but formats values with format
+func InterpolateWith(template string, values map[string]interface{}, format Formatter) (string, error) {
+	var b strings.Builder
+	b.Grow(len(template))
+
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+		if c != '$' || i+1 == len(template) {
+			b.WriteByte(c)
+			continue
+		}
+
+		switch template[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i++
+		case '{':
+			end := closingBrace(template, i+2)
+			if end < 0 {
+				return "", fmt.Errorf("unterminated placeholder at offset %d", i)
+			}
+			text, err := expandPlaceholder(template[i+2:end], values, format)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(text)
+			i = end
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String(), nil
+}
+
+// closingBrace returns the index of the '}' that closes a placeholder whose
+// body starts at start, skipping over nested placeholders
+func closingBrace(s string, start int) int {
+	depth := 1
+	for i := start; i < len(s); i++ {
+		switch {
+		case s[i] == '$' && i+1 < len(s) && s[i+1] == '{':
+			depth++
+			i++
+		case s[i] == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// expandPlaceholder evaluates the body of a single ${...} placeholder
+func expandPlaceholder(body string, values map[string]interface{}, format Formatter) (string, error) {
+	name, op, arg := body, "", ""
+	if i := strings.Index(body, ":"); i >= 0 && i+1 < len(body) && (body[i+1] == '-' || body[i+1] == '?') {
+		name, op, arg = body[:i], body[i:i+2], body[i+2:]
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("empty placeholder name in ${%s}", body)
+	}
+
+	text := ""
+	value, found := lookupPath(values, name)
+	if found {
+		text = format(value)
+	}
+
+	switch op {
+	case ":-":
+		if text == "" {
+			return InterpolateWith(arg, values, format)
+		}
+	case ":?":
+		if text == "" {
+			if arg == "" {
+				arg = "parameter not set"
+			}
+			return "", fmt.Errorf("%s: %s", name, arg)
+		}
+	}
+	return text, nil
+}
+
+// lookupPath resolves a dotted path such as "user.email" through nested
+// maps with string keys. A key containing the whole path takes precedence.
+func lookupPath(values map[string]interface{}, path string) (interface{}, bool) {
+	if v, ok := values[path]; ok {
+		return v, v != nil
+	}
+
+	var current interface{} = values
+	for _, key := range strings.Split(path, ".") {
+		m := reflect.ValueOf(current)
+		if m.Kind() != reflect.Map || m.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		v := m.MapIndex(reflect.ValueOf(key).Convert(m.Type().Key()))
+		if !v.IsValid() {
+			return nil, false
+		}
+		current = v.Interface()
+	}
+	return current, current != nil
+}
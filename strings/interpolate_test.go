@@ -0,0 +1,85 @@
+package strings
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestInterpolate(t *testing.T) {
+	values := map[string]interface{}{
+		"name":  "Ada",
+		"count": 3,
+		"ratio": 0.5,
+		"empty": "",
+		"user": map[string]interface{}{
+			"email": "ada@example.com",
+			"address": map[string]string{
+				"city": "London",
+			},
+		},
+		"app.port": 8080,
+	}
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{"Hello, ${name}!", "Hello, Ada!"},
+		{"${count} items at ${ratio}", "3 items at 0.5"},
+		{"${missing}", ""},
+		{"${missing:-fallback}", "fallback"},
+		{"${empty:-fallback}", "fallback"},
+		{"${name:-fallback}", "Ada"},
+		{"${missing:-${name}}", "Ada"},
+		{"${user.email}", "ada@example.com"},
+		{"${user.address.city}", "London"},
+		{"${user.phone:-n/a}", "n/a"},
+		{"${app.port}", "8080"},
+		{"$${name} costs $5", "${name} costs $5"},
+		{"ends with $", "ends with $"},
+		{"${name:?name is required}", "Ada"},
+		{"naïve ${name}", "naïve Ada"},
+	}
+
+	for _, test := range tests {
+		result, err := Interpolate(test.template, values)
+		if err != nil {
+			t.Errorf("Interpolate(%q) returned error: %v", test.template, err)
+			continue
+		}
+		if result != test.expected {
+			t.Errorf("Interpolate(%q) = %q; expected %q", test.template, result, test.expected)
+		}
+	}
+}
+
+func TestInterpolateErrors(t *testing.T) {
+	values := map[string]interface{}{"empty": ""}
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{"${missing:?missing is required}", "missing: missing is required"},
+		{"${empty:?}", "empty: parameter not set"},
+		{"${unterminated", "unterminated placeholder at offset 0"},
+		{"${}", "empty placeholder name in ${}"},
+	}
+
+	for _, test := range tests {
+		_, err := Interpolate(test.template, values)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("Interpolate(%q) error = %v; expected %q", test.template, err, test.expected)
+		}
+	}
+}
+
+func TestInterpolateWith(t *testing.T) {
+	values := map[string]interface{}{"price": 9.5}
+	format := func(v interface{}) string { return fmt.Sprintf("%.2f", v) }
+
+	result, err := InterpolateWith("Total: ${price}", values, format)
+	if err != nil || result != "Total: 9.50" {
+		t.Errorf("InterpolateWith = %q, %v; expected %q", result, err, "Total: 9.50")
+	}
+}
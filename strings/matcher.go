@@ -0,0 +1,411 @@
+package strings
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MatchKind selects which matches a Matcher reports when patterns overlap
+type MatchKind int
+
+const (
+	// MatchLeftmostLongest reports non-overlapping matches, scanning left to
+	// right and preferring the longest pattern at each starting position
+	MatchLeftmostLongest MatchKind = iota
+	// MatchOverlapping reports every occurrence of every pattern
+	MatchOverlapping
+)
+
+// MatcherOptions configures NewMatcher
+type MatcherOptions struct {
+	// IgnoreCase matches case-insensitively, folding both the patterns and
+	// the text to lower case as ContainsIgnoreCase does
+	IgnoreCase bool
+	// WholeWords only reports matches that are not preceded or followed by
+	// a letter, digit or underscore
+	WholeWords bool
+	// Kind selects overlapping or leftmost-longest matching
+	Kind MatchKind
+}
+
+// PatternMatch is an occurrence of a pattern found by a Matcher. Start and
+// End are byte offsets into the searched text.
+type PatternMatch struct {
+	Pattern int
+	Start   int
+	End     int
+}
+
+// Matcher finds many patterns in a single pass over the text using the
+// Aho-Corasick algorithm. A Matcher is safe for concurrent use.
+type Matcher struct {
+	patterns []string
+	opts     MatcherOptions
+	nodes    []acNode
+}
+
+// acNode is a state of the Aho-Corasick automaton
+type acNode struct {
+	next  map[rune]int
+	fail  int
+	depth int
+	// pattern is the index of the pattern ending at this node, or -1
+	pattern int
+	// output is the nearest node on the fail chain that ends a pattern
+	output int
+}
+
+// NewMatcher compiles patterns into a Matcher. Empty patterns never match.
+func NewMatcher(patterns []string, opts MatcherOptions) *Matcher {
+	m := &Matcher{
+		patterns: append([]string(nil), patterns...),
+		opts:     opts,
+		nodes:    []acNode{{next: map[rune]int{}, pattern: -1, output: -1}},
+	}
+
+	for i, p := range patterns {
+		state := 0
+		for _, r := range p {
+			r = m.fold(r)
+			next, ok := m.nodes[state].next[r]
+			if !ok {
+				next = len(m.nodes)
+				m.nodes = append(m.nodes, acNode{
+					next:    map[rune]int{},
+					depth:   m.nodes[state].depth + 1,
+					pattern: -1,
+					output:  -1,
+				})
+				m.nodes[state].next[r] = next
+			}
+			state = next
+		}
+		if state != 0 && m.nodes[state].pattern < 0 {
+			m.nodes[state].pattern = i
+		}
+	}
+
+	// Breadth-first construction of the failure and output links
+	queue := []int{}
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for r, child := range m.nodes[state].next {
+			fail := m.nodes[state].fail
+			for {
+				if next, ok := m.nodes[fail].next[r]; ok {
+					m.nodes[child].fail = next
+					break
+				}
+				if fail == 0 {
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+			f := m.nodes[child].fail
+			if m.nodes[f].pattern >= 0 {
+				m.nodes[child].output = f
+			} else {
+				m.nodes[child].output = m.nodes[f].output
+			}
+			queue = append(queue, child)
+		}
+	}
+
+	return m
+}
+
+func (m *Matcher) fold(r rune) rune {
+	if m.opts.IgnoreCase {
+		return unicode.ToLower(r)
+	}
+	return r
+}
+
+// step advances the automaton from state on rune r
+func (m *Matcher) step(state int, r rune) int {
+	r = m.fold(r)
+	for {
+		if next, ok := m.nodes[state].next[r]; ok {
+			return next
+		}
+		if state == 0 {
+			return 0
+		}
+		state = m.nodes[state].fail
+	}
+}
+
+// isMatchWordRune reports whether r counts as part of a word for WholeWords
+func isMatchWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// runeText is text decoded into runes along with the byte offset of each
+// rune; offs has one extra entry holding the total length
+type runeText struct {
+	runes []rune
+	offs  []int
+}
+
+func newRuneText(s string) runeText {
+	t := runeText{
+		runes: make([]rune, 0, len(s)),
+		offs:  make([]int, 0, len(s)+1),
+	}
+	for i, r := range s {
+		t.runes = append(t.runes, r)
+		t.offs = append(t.offs, i)
+	}
+	t.offs = append(t.offs, len(s))
+	return t
+}
+
+// wholeWord reports whether runes[start:end] stands alone as a word. before
+// is the rune preceding runes[0], or -1 at the start of the text.
+func (m *Matcher) wholeWord(runes []rune, start, end int, before rune) bool {
+	if !m.opts.WholeWords {
+		return true
+	}
+	left := before
+	if start > 0 {
+		left = runes[start-1]
+	}
+	if left >= 0 && isMatchWordRune(left) {
+		return false
+	}
+	return end >= len(runes) || !isMatchWordRune(runes[end])
+}
+
+// runeMatch is a match with rune offsets
+type runeMatch struct {
+	pattern, start, end int
+}
+
+// scanOverlapping reports every match in runes
+func (m *Matcher) scanOverlapping(runes []rune) []runeMatch {
+	var matches []runeMatch
+	state := 0
+	for pos, r := range runes {
+		state = m.step(state, r)
+		for node := state; node > 0; node = m.nodes[node].output {
+			n := m.nodes[node]
+			if n.pattern < 0 {
+				continue
+			}
+			start := pos + 1 - n.depth
+			if m.wholeWord(runes, start, pos+1, -1) {
+				matches = append(matches, runeMatch{n.pattern, start, pos + 1})
+			}
+		}
+	}
+	return matches
+}
+
+// scanLeftmostLongest reports non-overlapping leftmost-longest matches.
+// Unless final is set the last rune is kept as lookahead and the scan stops
+// where the outcome depends on text not yet seen; consumed is the number of
+// runes that are fully decided and need not be scanned again.
+func (m *Matcher) scanLeftmostLongest(runes []rune, before rune, final bool) (matches []runeMatch, consumed int) {
+	limit := len(runes)
+	if !final && limit > 0 {
+		limit--
+	}
+
+	state := 0
+	var cand *runeMatch
+	for pos := 0; ; pos++ {
+		if pos >= limit {
+			if !final || cand == nil {
+				break
+			}
+			// End of text: take the candidate and look for more after it
+			matches = append(matches, *cand)
+			pos, state, cand = cand.end, 0, nil
+			if pos >= limit {
+				break
+			}
+		}
+		state = m.step(state, runes[pos])
+
+		// Matches ending here, longest (and so leftmost) first
+		for node := state; node > 0; node = m.nodes[node].output {
+			n := m.nodes[node]
+			if n.pattern < 0 {
+				continue
+			}
+			start := pos + 1 - n.depth
+			if !m.wholeWord(runes, start, pos+1, before) {
+				continue
+			}
+			if cand == nil || start < cand.start || (start == cand.start && pos+1 > cand.end) {
+				cand = &runeMatch{n.pattern, start, pos + 1}
+			}
+			break
+		}
+
+		// No match still in progress can start at or before the candidate
+		if cand != nil && pos+1-m.nodes[state].depth > cand.start {
+			matches = append(matches, *cand)
+			pos = cand.end - 1
+			state = 0
+			cand = nil
+		}
+	}
+
+	if final {
+		return matches, len(runes)
+	}
+	consumed = limit - m.nodes[state].depth
+	if cand != nil && cand.start < consumed {
+		consumed = cand.start
+	}
+	return matches, consumed
+}
+
+// FindAll returns the matches of the patterns in s, in order of their end
+// position for MatchOverlapping and of their start position otherwise
+func (m *Matcher) FindAll(s string) []PatternMatch {
+	text := newRuneText(s)
+
+	var found []runeMatch
+	if m.opts.Kind == MatchOverlapping {
+		found = m.scanOverlapping(text.runes)
+	} else {
+		found, _ = m.scanLeftmostLongest(text.runes, -1, true)
+	}
+
+	matches := make([]PatternMatch, len(found))
+	for i, f := range found {
+		matches[i] = PatternMatch{Pattern: f.pattern, Start: text.offs[f.start], End: text.offs[f.end]}
+	}
+	return matches
+}
+
+// Contains reports whether any pattern occurs in s
+func (m *Matcher) Contains(s string) bool {
+	return len(m.FindAll(s)) > 0
+}
+
+// Replacer substitutes every match of a Matcher's patterns with the
+// corresponding replacement. Matches never overlap: the Matcher's Kind is
+// ignored and leftmost-longest matching is always used.
+type Replacer struct {
+	matcher      *Matcher
+	replacements []string
+}
+
+// NewReplacer returns a Replacer that writes replacements[i] in place of
+// every match of pattern i
+func (m *Matcher) NewReplacer(replacements []string) (*Replacer, error) {
+	if len(replacements) != len(m.patterns) {
+		return nil, fmt.Errorf("got %d replacements for %d patterns", len(replacements), len(m.patterns))
+	}
+	return &Replacer{matcher: m, replacements: append([]string(nil), replacements...)}, nil
+}
+
+// ReplaceAll returns a copy of s with every match replaced
+func (r *Replacer) ReplaceAll(s string) string {
+	text := newRuneText(s)
+	matches, _ := r.matcher.scanLeftmostLongest(text.runes, -1, true)
+
+	out := make([]byte, 0, len(s))
+	last := 0
+	for _, match := range matches {
+		out = append(out, s[last:text.offs[match.start]]...)
+		out = append(out, r.replacements[match.pattern]...)
+		last = text.offs[match.end]
+	}
+	out = append(out, s[last:]...)
+	return string(out)
+}
+
+// replaceChunk is the number of runes the streaming Replacer buffers before
+// scanning
+const replaceChunk = 4096
+
+// Replace copies src to dst, replacing every match on the way. Only a
+// window of a few kilobytes plus the longest pattern is held in memory.
+// It returns the number of bytes written.
+func (r *Replacer) Replace(dst io.Writer, src io.Reader) (int64, error) {
+	br := bufio.NewReader(src)
+	bw := bufio.NewWriter(dst)
+
+	var written int64
+	var runes []rune
+	var raw []byte
+	var offs []int
+	var before rune = -1
+
+	// at returns the byte offset of rune i in raw
+	at := func(i int) int {
+		if i == len(runes) {
+			return len(raw)
+		}
+		return offs[i]
+	}
+	write := func(p []byte) {
+		n, _ := bw.Write(p)
+		written += int64(n)
+	}
+
+	flush := func(final bool) {
+		matches, consumed := r.matcher.scanLeftmostLongest(runes, before, final)
+
+		last := 0
+		for _, match := range matches {
+			write(raw[at(last):at(match.start)])
+			write([]byte(r.replacements[match.pattern]))
+			last = match.end
+		}
+		if consumed > last {
+			write(raw[at(last):at(consumed)])
+		}
+		if consumed > 0 {
+			before = runes[consumed-1]
+		}
+
+		// Keep the undecided tail for the next round
+		base := at(consumed)
+		raw = append(raw[:0], raw[base:]...)
+		runes = append(runes[:0], runes[consumed:]...)
+		offs = offs[:copy(offs, offs[consumed:])]
+		for i := range offs {
+			offs[i] -= base
+		}
+	}
+
+	for {
+		c, size, err := br.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return written, err
+		}
+		offs = append(offs, len(raw))
+		runes = append(runes, c)
+		if c == utf8.RuneError && size == 1 {
+			// Keep invalid bytes as they were rather than re-encoding them
+			br.UnreadRune()
+			b, _ := br.ReadByte()
+			raw = append(raw, b)
+		} else {
+			raw = utf8.AppendRune(raw, c)
+		}
+
+		if len(runes) >= replaceChunk {
+			flush(false)
+		}
+	}
+
+	// bufio.Writer keeps the first write error and reports it on Flush
+	flush(true)
+	return written, bw.Flush()
+}
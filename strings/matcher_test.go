@@ -0,0 +1,192 @@
+package strings
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatcherFindAll(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		opts     MatcherOptions
+		input    string
+		expected []PatternMatch
+	}{
+		{
+			[]string{"he", "she", "his", "hers"},
+			MatcherOptions{Kind: MatchOverlapping},
+			"ushers",
+			[]PatternMatch{{1, 1, 4}, {0, 2, 4}, {3, 2, 6}},
+		},
+		{
+			[]string{"he", "she", "his", "hers"},
+			MatcherOptions{},
+			"ushers",
+			[]PatternMatch{{1, 1, 4}},
+		},
+		{
+			[]string{"abcd", "b", "c"},
+			MatcherOptions{},
+			"abc",
+			[]PatternMatch{{1, 1, 2}, {2, 2, 3}},
+		},
+		{
+			[]string{"new", "new york", "york"},
+			MatcherOptions{},
+			"new york, new jersey",
+			[]PatternMatch{{1, 0, 8}, {0, 10, 13}},
+		},
+		{
+			[]string{"secret"},
+			MatcherOptions{IgnoreCase: true},
+			"Top SECRET and Secret",
+			[]PatternMatch{{0, 4, 10}, {0, 15, 21}},
+		},
+		{
+			[]string{"cat"},
+			MatcherOptions{WholeWords: true},
+			"cat concat cat_x cat.",
+			[]PatternMatch{{0, 0, 3}, {0, 17, 20}},
+		},
+		{
+			[]string{"über", "straße"},
+			MatcherOptions{IgnoreCase: true},
+			"ÜBER die Straße",
+			[]PatternMatch{{0, 0, 5}, {1, 10, 17}},
+		},
+		{
+			[]string{"", "x"},
+			MatcherOptions{},
+			"axb",
+			[]PatternMatch{{1, 1, 2}},
+		},
+	}
+
+	for _, test := range tests {
+		m := NewMatcher(test.patterns, test.opts)
+		result := m.FindAll(test.input)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("FindAll(%q) with %q %+v = %v; expected %v", test.input, test.patterns, test.opts, result, test.expected)
+		}
+	}
+}
+
+func TestMatcherContains(t *testing.T) {
+	m := NewMatcher([]string{"password", "token"}, MatcherOptions{IgnoreCase: true})
+	if !m.Contains("my PASSWORD is") {
+		t.Error("Contains should find PASSWORD case-insensitively")
+	}
+	if m.Contains("nothing here") {
+		t.Error("Contains should not match unrelated text")
+	}
+}
+
+func TestReplacer(t *testing.T) {
+	m := NewMatcher([]string{"alice", "bob", "bobby"}, MatcherOptions{IgnoreCase: true, WholeWords: true})
+	r, err := m.NewReplacer([]string{"[A]", "[B]", "[BB]"})
+	if err != nil {
+		t.Fatalf("NewReplacer returned error: %v", err)
+	}
+
+	input := "Alice met Bobby and bob, not bobcat."
+	expected := "[A] met [BB] and [B], not bobcat."
+	if result := r.ReplaceAll(input); result != expected {
+		t.Errorf("ReplaceAll(%q) = %q; expected %q", input, result, expected)
+	}
+
+	var out bytes.Buffer
+	n, err := r.Replace(&out, strings.NewReader(input))
+	if err != nil || out.String() != expected || n != int64(len(expected)) {
+		t.Errorf("Replace(%q) = %q, %d, %v; expected %q", input, out.String(), n, err, expected)
+	}
+
+	if _, err := m.NewReplacer([]string{"only one"}); err == nil {
+		t.Error("NewReplacer should reject a replacement count mismatch")
+	}
+}
+
+// naiveLeftmostLongest is the reference definition of leftmost-longest
+// matching: at each position take the longest pattern, else move on
+func naiveLeftmostLongest(patterns []string, s string) []PatternMatch {
+	var matches []PatternMatch
+	for i := 0; i < len(s); {
+		best := -1
+		for p, pattern := range patterns {
+			if pattern != "" && strings.HasPrefix(s[i:], pattern) && (best < 0 || len(pattern) > len(patterns[best])) {
+				best = p
+			}
+		}
+		if best < 0 {
+			i++
+			continue
+		}
+		matches = append(matches, PatternMatch{best, i, i + len(patterns[best])})
+		i += len(patterns[best])
+	}
+	return matches
+}
+
+func TestMatcherAgainstNaive(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randomString := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = "abc"[rng.Intn(3)]
+		}
+		return string(b)
+	}
+
+	for round := 0; round < 200; round++ {
+		patterns := make([]string, 1+rng.Intn(6))
+		for i := range patterns {
+			patterns[i] = randomString(1 + rng.Intn(5))
+		}
+		input := randomString(rng.Intn(60))
+
+		m := NewMatcher(patterns, MatcherOptions{})
+		result := m.FindAll(input)
+		expected := naiveLeftmostLongest(patterns, input)
+		if len(result) == 0 && len(expected) == 0 {
+			continue
+		}
+		// Duplicate patterns are reported under their first index
+		for i := range expected {
+			for p := range patterns {
+				if patterns[p] == patterns[expected[i].Pattern] {
+					expected[i].Pattern = p
+					break
+				}
+			}
+		}
+		if !reflect.DeepEqual(result, expected) {
+			t.Fatalf("FindAll(%q) with %q = %v; expected %v", input, patterns, result, expected)
+		}
+	}
+}
+
+func TestReplacerStreamsLargeInput(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	var b strings.Builder
+	words := []string{"foo", "bar", "foobar", "baz", "qux", "日本", "x"}
+	for b.Len() < 50000 {
+		b.WriteString(words[rng.Intn(len(words))])
+		if rng.Intn(3) == 0 {
+			b.WriteByte(' ')
+		}
+	}
+	input := b.String() + "\xff"
+
+	m := NewMatcher([]string{"foo", "foobar", "barbaz", "日本"}, MatcherOptions{})
+	r, _ := m.NewReplacer([]string{"1", "2", "3", "4"})
+
+	var out bytes.Buffer
+	if _, err := r.Replace(&out, strings.NewReader(input)); err != nil {
+		t.Fatalf("Replace returned error: %v", err)
+	}
+	if expected := r.ReplaceAll(input); out.String() != expected {
+		t.Error("streaming Replace output differs from ReplaceAll")
+	}
+}
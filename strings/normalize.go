@@ -0,0 +1,109 @@
+package strings
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizationForm is a Unicode normalization form as defined by UAX #15
+type NormalizationForm int
+
+// The four Unicode normalization forms
+const (
+	// NFC composes characters: "é" becomes "é"
+	NFC NormalizationForm = iota
+	// NFD decomposes characters: "é" becomes "é"
+	NFD
+	// NFKC is NFC after also replacing compatibility characters such as
+	// ligatures and fullwidth letters: "ﬁ" becomes "fi"
+	NFKC
+	// NFKD is the decomposed counterpart of NFKC
+	NFKD
+)
+
+func (f NormalizationForm) form() norm.Form {
+	switch f {
+	case NFD:
+		return norm.NFD
+	case NFKC:
+		return norm.NFKC
+	case NFKD:
+		return norm.NFKD
+	}
+	return norm.NFC
+}
+
+// Normalize converts s to the given normalization form
+func Normalize(s string, form NormalizationForm) string {
+	return form.form().String(s)
+}
+
+// CaseFold applies full Unicode case folding, which unlike strings.ToLower
+// also maps characters that fold to several: "ß" and "SS" both become "ss"
+func CaseFold(s string) string {
+	return cases.Fold().String(s)
+}
+
+// EqualFold reports whether a and b are equal under Unicode canonical
+// caseless matching, so that precomposed and decomposed forms as well as
+// full case folding ("Straße" and "STRASSE") compare equal
+func EqualFold(a, b string) bool {
+	return caseless(a, NFD) == caseless(b, NFD)
+}
+
+// caseless normalizes and case-folds s for comparison. The decomposition
+// before folding catches characters whose folding is only defined for
+// their decomposed form.
+func caseless(s string, form NormalizationForm) string {
+	return Normalize(CaseFold(norm.NFD.String(s)), form)
+}
+
+// confusables maps characters to the prototype they are visually confused
+// with. It is a hand-picked subset of the Unicode TR39 confusables data,
+// covering the Latin, Cyrillic and Greek lookalikes most used in spoofing.
+var confusables = map[rune]string{
+	// Latin letters and digits that resemble each other
+	'0': "O", '1': "l", 'I': "l", '|': "l", 'ǀ': "l",
+	'ı': "i", 'ɑ': "a", 'ɡ': "g", 'ʏ': "y",
+
+	// Cyrillic
+	'а': "a", 'в': "B", 'е': "e", 'һ': "h", 'і': "i", 'ј': "j", 'к': "K",
+	'м': "M", 'н': "H", 'о': "o", 'р': "p", 'с': "c", 'т': "T", 'у': "y",
+	'х': "x", 'ѕ': "s", 'ԁ': "d", 'ԛ': "q", 'ԝ': "w", 'ӏ': "l", 'ь': "b",
+	'А': "A", 'В': "B", 'Е': "E", 'З': "3", 'І': "l", 'Ј': "J", 'К': "K",
+	'М': "M", 'Н': "H", 'О': "O", 'Р': "P", 'С': "C", 'Т': "T", 'Х': "X",
+	'Ѕ': "S", 'Ү': "Y", 'Ԁ': "d", 'Ӏ': "l",
+
+	// Greek
+	'α': "a", 'ο': "o", 'ν': "v", 'ρ': "p", 'υ': "u", 'ι': "i", 'κ': "K",
+	'Α': "A", 'Β': "B", 'Ε': "E", 'Ζ': "Z", 'Η': "H", 'Ι': "l", 'Κ': "K",
+	'Μ': "M", 'Ν': "N", 'Ο': "O", 'Ρ': "P", 'Τ': "T", 'Υ': "Y", 'Χ': "X",
+}
+
+// ConfusableKey returns a key under which strings that look alike compare
+// equal: the compatibility decomposition of s with every confusable
+// character replaced by its prototype, and "rn" read as "m". It is a small
+// heuristic modelled on the TR39 skeleton, not the full TR39 algorithm, so
+// lookalikes outside its table are not caught. Case is preserved, so
+// combine with CaseFold to also ignore case.
+func ConfusableKey(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFKD.String(s) {
+		if p, ok := confusables[r]; ok {
+			b.WriteString(p)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	// TR39 maps the sequence "rn" to the prototype "m"
+	return norm.NFD.String(strings.ReplaceAll(b.String(), "rn", "m"))
+}
+
+// IsConfusable reports whether a and b are visually confusable, such as
+// "paypal" and "раураl" written with Cyrillic letters, by comparing their
+// ConfusableKey
+func IsConfusable(a, b string) bool {
+	return ConfusableKey(a) == ConfusableKey(b)
+}
@@ -0,0 +1,136 @@
+package strings
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input    string
+		form     NormalizationForm
+		expected string
+	}{
+		{"é", NFC, "é"},
+		{"é", NFD, "é"},
+		{"ﬁle", NFKC, "file"},
+		{"Ａé", NFKD, "Aé"},
+		{"plain", NFC, "plain"},
+	}
+
+	for _, test := range tests {
+		result := Normalize(test.input, test.form)
+		if result != test.expected {
+			t.Errorf("Normalize(%q, %d) = %q; expected %q", test.input, test.form, result, test.expected)
+		}
+	}
+}
+
+func TestCaseFold(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Straße", "strasse"},
+		{"HELLO", "hello"},
+		{"ΣΊΣΥΦΟΣ", "σίσυφοσ"},
+	}
+
+	for _, test := range tests {
+		result := CaseFold(test.input)
+		if result != test.expected {
+			t.Errorf("CaseFold(%q) = %q; expected %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestEqualFold(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"Straße", "STRASSE", true},
+		{"café", "CAFÉ", true},
+		{"hello", "Hello", true},
+		{"hello", "help", false},
+		{"cafe", "café", false},
+	}
+
+	for _, test := range tests {
+		result := EqualFold(test.a, test.b)
+		if result != test.expected {
+			t.Errorf("EqualFold(%q, %q) = %v; expected %v", test.a, test.b, result, test.expected)
+		}
+	}
+}
+
+func TestIsConfusable(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"paypal", "раураl", true},
+		{"apple", "аррӏе", true},
+		{"google", "g00gle", false},
+		{"GOOGLE", "G00GLE", true},
+		{"paypal", "paypa1", true},
+		{"modern", "rnodern", true},
+		{"burn", "bum", true},
+		{"arm", "am", false},
+		{"ｓｃｏｐｅ", "scope", true},
+		{"alice", "bob", false},
+	}
+
+	for _, test := range tests {
+		result := IsConfusable(test.a, test.b)
+		if result != test.expected {
+			t.Errorf("IsConfusable(%q, %q) = %v; expected %v (keys %q, %q)", test.a, test.b, result, test.expected, ConfusableKey(test.a), ConfusableKey(test.b))
+		}
+	}
+}
+
+func TestExistingHelperSignatures(t *testing.T) {
+	// The normalizing variants are separate functions, so these still fit
+	// their original function types
+	var _ func(string) bool = IsPalindrome
+	var _ func(string, string) bool = ContainsIgnoreCase
+}
+
+func TestConfusableKey(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"rn", "m"},
+		{"m", "m"},
+		{"раураl", "paypal"},
+		{"ﬁrn", "fim"},
+	}
+
+	for _, test := range tests {
+		result := ConfusableKey(test.input)
+		if result != test.expected {
+			t.Errorf("ConfusableKey(%q) = %q; expected %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestComparisonHelpersWithNormalization(t *testing.T) {
+	if ContainsIgnoreCase("STRASSE", "straße") {
+		t.Error("ContainsIgnoreCase should keep its simple lower-casing")
+	}
+	if !ContainsIgnoreCaseWith("GROSSE STRASSE", "straße", NFC) {
+		t.Error("ContainsIgnoreCaseWith with NFC should apply full case folding")
+	}
+	if !ContainsIgnoreCaseWith("café au lait", "CAFÉ", NFC) {
+		t.Error("ContainsIgnoreCaseWith with NFC should match decomposed accents")
+	}
+
+	mixed := "été"
+	if IsPalindrome(mixed) {
+		t.Error("IsPalindrome should compare code points")
+	}
+	if !IsPalindromeWith(mixed, NFC) {
+		t.Error("IsPalindromeWith with NFC should treat composed and decomposed accents alike")
+	}
+	if !IsPalindromeWith("Was it a car\tor a cat I saw", NFD) {
+		t.Error("IsPalindromeWith should ignore all whitespace")
+	}
+}
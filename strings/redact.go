@@ -0,0 +1,287 @@
+package strings
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yourusername/goutils/validation"
+)
+
+// MaskOptions configures Mask
+type MaskOptions struct {
+	// Char replaces hidden characters. It defaults to '*'.
+	Char rune
+	// KeepStart and KeepEnd are the number of characters left visible at
+	// each end. When they would reveal the whole value, everything is masked.
+	KeepStart int
+	KeepEnd   int
+	// KeepSeparators leaves characters other than letters and digits, such
+	// as the dashes of a card number, visible and out of the count
+	KeepSeparators bool
+	// EmailDomain masks only the local part of a valid email address
+	EmailDomain bool
+}
+
+// Mask hides part of a sensitive value, for example "4111********1111" for
+// a card number with KeepStart and KeepEnd of 4, or "j***@example.com" for
+// an email with KeepStart 1 and EmailDomain set
+func Mask(s string, opts MaskOptions) string {
+	if opts.EmailDomain && validation.IsEmail(s) {
+		at := strings.LastIndexByte(s, '@')
+		local := opts
+		local.EmailDomain = false
+		return Mask(s[:at], local) + s[at:]
+	}
+
+	char := opts.Char
+	if char == 0 {
+		char = '*'
+	}
+
+	runes := []rune(s)
+	maskable := func(r rune) bool {
+		return !opts.KeepSeparators || unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+	total := 0
+	for _, r := range runes {
+		if maskable(r) {
+			total++
+		}
+	}
+
+	keepStart, keepEnd := opts.KeepStart, opts.KeepEnd
+	if keepStart < 0 {
+		keepStart = 0
+	}
+	if keepEnd < 0 {
+		keepEnd = 0
+	}
+	if keepStart+keepEnd >= total {
+		keepStart, keepEnd = 0, 0
+	}
+
+	seen := 0
+	for i, r := range runes {
+		if !maskable(r) {
+			continue
+		}
+		if seen >= keepStart && seen < total-keepEnd {
+			runes[i] = char
+		}
+		seen++
+	}
+	return string(runes)
+}
+
+// PIIKind identifies a kind of personal or secret data found by a Redactor
+type PIIKind string
+
+// Kinds of data a Redactor detects
+const (
+	PIIEmail      PIIKind = "EMAIL"
+	PIIPhone      PIIKind = "PHONE"
+	PIICreditCard PIIKind = "CREDIT_CARD"
+	PIIIP         PIIKind = "IP"
+	PIIAPIKey     PIIKind = "API_KEY"
+)
+
+// Redaction is a piece of sensitive data found in a text. Start and End are
+// byte offsets.
+type Redaction struct {
+	Kind  PIIKind
+	Start int
+	End   int
+	Value string
+}
+
+// piiDetector finds candidates with a permissive pattern and confirms them
+// with the validation package, so that redaction and validation agree
+type piiDetector struct {
+	kind    PIIKind
+	pattern *regexp.Regexp
+	valid   func(string) bool
+	// spans, if set, lists the candidates to validate within a match, as
+	// offsets relative to it, instead of the whole match
+	spans func(match string) [][2]int
+}
+
+var piiDetectors = []piiDetector{
+	{
+		PIIEmail,
+		regexp.MustCompile(`[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`),
+		validation.IsEmail,
+		nil,
+	},
+	{
+		PIICreditCard,
+		regexp.MustCompile(`\d(?:[ -]?\d)*`),
+		validation.IsCreditCard,
+		digitGroupSpans,
+	},
+	{
+		PIIPhone,
+		regexp.MustCompile(`(?:\+?1[-.\s]?)?(?:\(\d{3}\)|\d{3})[-.\s]?\d{3}[-.\s]?\d{4}`),
+		validation.IsPhone,
+		nil,
+	},
+	{
+		PIIIP,
+		regexp.MustCompile(`(?:\d{1,3}\.){3}\d{1,3}|[0-9A-Fa-f]{0,4}(?::[0-9A-Fa-f]{0,4}){2,7}`),
+		validation.IsIP,
+		nil,
+	},
+	{
+		PIIAPIKey,
+		regexp.MustCompile(`(?:sk|pk|rk)_(?:live|test)_[0-9A-Za-z]{16,}|AKIA[0-9A-Z]{16}|gh[pousr]_[0-9A-Za-z]{36,}|xox[abprs]-[0-9A-Za-z-]{10,}|[A-Za-z0-9_]{32,}`),
+		isAPIKeyShaped,
+		nil,
+	},
+}
+
+// digitGroupSpans splits a run of digit groups, such as "4532 0151 1283
+// 0366 123", at its separators and returns every run of whole groups
+// holding 13 to 19 digits, longest first at each start. A card number next
+// to other digits is then still tried on its own.
+func digitGroupSpans(match string) [][2]int {
+	var starts, ends []int
+	for i := 0; i < len(match); {
+		j := i
+		for j < len(match) && match[j] >= '0' && match[j] <= '9' {
+			j++
+		}
+		starts, ends = append(starts, i), append(ends, j)
+		i = j + 1
+	}
+
+	var spans [][2]int
+	for first := range starts {
+		var candidates [][2]int
+		digits := 0
+		for last := first; last < len(starts); last++ {
+			digits += ends[last] - starts[last]
+			if digits > 19 {
+				break
+			}
+			if digits >= 13 {
+				candidates = append(candidates, [2]int{starts[first], ends[last]})
+			}
+		}
+		for i := len(candidates) - 1; i >= 0; i-- {
+			spans = append(spans, candidates[i])
+		}
+	}
+	return spans
+}
+
+// isAPIKeyShaped accepts long tokens that mix letters and digits, which
+// rules out ordinary long words and identifiers
+func isAPIKeyShaped(s string) bool {
+	hasLetter := strings.IndexFunc(s, unicode.IsLetter) >= 0
+	hasDigit := strings.IndexFunc(s, unicode.IsDigit) >= 0
+	return hasLetter && hasDigit
+}
+
+// Redactor finds and replaces personal data and secrets in free text
+type Redactor struct {
+	// Kinds limits detection to the listed kinds. Empty means all kinds.
+	Kinds []PIIKind
+	// Replace produces the text written in place of a finding. It defaults
+	// to "[REDACTED:KIND]".
+	Replace func(r Redaction) string
+}
+
+// NewRedactor returns a Redactor for the given kinds, or all kinds if none
+// are given
+func NewRedactor(kinds ...PIIKind) *Redactor {
+	return &Redactor{Kinds: kinds}
+}
+
+func (r *Redactor) enabled(kind PIIKind) bool {
+	if len(r.Kinds) == 0 {
+		return true
+	}
+	for _, k := range r.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the sensitive data in text in order of position. When
+// findings overlap the one starting first, then the longest, wins.
+func (r *Redactor) Find(text string) []Redaction {
+	var found []Redaction
+	for _, d := range piiDetectors {
+		if !r.enabled(d.kind) {
+			continue
+		}
+		for _, loc := range d.pattern.FindAllStringIndex(text, -1) {
+			spans := [][2]int{{0, loc[1] - loc[0]}}
+			if d.spans != nil {
+				spans = d.spans(text[loc[0]:loc[1]])
+			}
+			// Overlapping candidates are resolved below like findings of
+			// different kinds
+			for _, span := range spans {
+				start, end := loc[0]+span[0], loc[0]+span[1]
+				value := text[start:end]
+				if !standsAlone(text, start, end) || !d.valid(value) {
+					continue
+				}
+				found = append(found, Redaction{Kind: d.kind, Start: start, End: end, Value: value})
+			}
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].Start != found[j].Start {
+			return found[i].Start < found[j].Start
+		}
+		return found[i].End > found[j].End
+	})
+
+	var result []Redaction
+	end := 0
+	for _, f := range found {
+		if f.Start >= end {
+			result = append(result, f)
+			end = f.End
+		}
+	}
+	return result
+}
+
+// standsAlone reports whether text[start:end] is not glued to surrounding
+// letters or digits, so that a run of digits inside a longer number is not
+// mistaken for a phone or card number
+func standsAlone(text string, start, end int) bool {
+	if before, _ := utf8.DecodeLastRuneInString(text[:start]); start > 0 && isMatchWordRune(before) {
+		return false
+	}
+	if after, _ := utf8.DecodeRuneInString(text[end:]); end < len(text) && isMatchWordRune(after) {
+		return false
+	}
+	return true
+}
+
+// Redact returns text with every finding replaced
+func (r *Redactor) Redact(text string) string {
+	replace := r.Replace
+	if replace == nil {
+		replace = func(f Redaction) string { return "[REDACTED:" + string(f.Kind) + "]" }
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, f := range r.Find(text) {
+		b.WriteString(text[last:f.Start])
+		b.WriteString(replace(f))
+		last = f.End
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
@@ -0,0 +1,87 @@
+package strings
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		input    string
+		opts     MaskOptions
+		expected string
+	}{
+		{"4111111111111111", MaskOptions{KeepStart: 4, KeepEnd: 4}, "4111********1111"},
+		{"4111-1111-1111-1111", MaskOptions{KeepEnd: 4, KeepSeparators: true}, "****-****-****-1111"},
+		{"john@example.com", MaskOptions{KeepStart: 1, EmailDomain: true}, "j***@example.com"},
+		{"not-an-email", MaskOptions{KeepStart: 1, EmailDomain: true}, "n***********"},
+		{"secret", MaskOptions{Char: '#'}, "######"},
+		{"abc", MaskOptions{KeepStart: 2, KeepEnd: 2}, "***"},
+		{"pässwörd", MaskOptions{KeepStart: 1, KeepEnd: 1}, "p******d"},
+		{"", MaskOptions{KeepStart: 1}, ""},
+	}
+
+	for _, test := range tests {
+		result := Mask(test.input, test.opts)
+		if result != test.expected {
+			t.Errorf("Mask(%q, %+v) = %q; expected %q", test.input, test.opts, result, test.expected)
+		}
+	}
+}
+
+func TestRedactorFind(t *testing.T) {
+	text := "Mail jane.doe@example.com or call (555) 123-4567. Card 4532015112830366, host 192.168.1.10, key sk_live_abcdefghijklmnop1234."
+	expected := []Redaction{
+		{PIIEmail, 5, 25, "jane.doe@example.com"},
+		{PIIPhone, 34, 48, "(555) 123-4567"},
+		{PIICreditCard, 55, 71, "4532015112830366"},
+		{PIIIP, 78, 90, "192.168.1.10"},
+		{PIIAPIKey, 96, 124, "sk_live_abcdefghijklmnop1234"},
+	}
+
+	result := NewRedactor().Find(text)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Find() = %+v; expected %+v", result, expected)
+	}
+}
+
+func TestRedactorAgreesWithValidation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		// Fails the Luhn check, so it is not a card number
+		{"order 4532015112830367 shipped", "order 4532015112830367 shipped"},
+		{"ip 999.1.1.1 is invalid", "ip 999.1.1.1 is invalid"},
+		{"ipv6 2001:db8::1 ok", "ipv6 [REDACTED:IP] ok"},
+		{"at 10:30:00 sharp", "at 10:30:00 sharp"},
+		{"id 123456789012345678901234 here", "id 123456789012345678901234 here"},
+		{"user@example.com", "[REDACTED:EMAIL]"},
+		// A card next to other digits is still found
+		{"card 4532015112830366 123", "card [REDACTED:CREDIT_CARD] 123"},
+		{"ref 12 4532-0151-1283-0366", "ref 12 [REDACTED:CREDIT_CARD]"},
+		{"ids 4532015112830366 4532015112830366", "ids [REDACTED:CREDIT_CARD] [REDACTED:CREDIT_CARD]"},
+		{"ids 4532 0151 1283 0366 4111 1111 1111 1111", "ids [REDACTED:CREDIT_CARD] [REDACTED:CREDIT_CARD]"},
+	}
+
+	r := NewRedactor()
+	for _, test := range tests {
+		result := r.Redact(test.input)
+		if result != test.expected {
+			t.Errorf("Redact(%q) = %q; expected %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestRedactorKindsAndReplace(t *testing.T) {
+	r := NewRedactor(PIIEmail)
+	r.Replace = func(f Redaction) string {
+		return Mask(f.Value, MaskOptions{KeepStart: 1, EmailDomain: true})
+	}
+
+	input := "from bob@example.com at 10.0.0.1"
+	expected := "from b**@example.com at 10.0.0.1"
+	if result := r.Redact(input); result != expected {
+		t.Errorf("Redact(%q) = %q; expected %q", input, result, expected)
+	}
+}
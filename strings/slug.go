@@ -0,0 +1,202 @@
+package strings
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// latinFold maps U+00C0..U+024F to the ASCII letter each character is
+// based on. A '.' marks characters that are either listed in
+// transliterationExceptions or have no ASCII equivalent.
+const latinFold = "" +
+	"AAAAAA.CEEEEIIII.NOOOOO..UUUUY..aaaaaa.ceeeeiiii.nooooo..uuuuy.y" + // U+00C0
+	"AaAaAaCcCcCcCcDd..EeEeEeEeEeGgGgGgGgHh..IiIiIiIiI...JjKk.LlLlLl." + // U+0100
+	"...NnNnNn...OoOoOo..RrRrRrSsSsSsSsTtTt..UuUuUuUuUuUuWwYyYZzZzZz." + // U+0140
+	"................................Oo.............Uu..............." + // U+0180
+	".............AaIiOoUuUuUuUuUu.AaAa....GgKkOoOo..j...Gg..NnAa...." + // U+01C0
+	"AaAaEeEeIiIiOoOoRrRrUuUuSsTt..Hh......AaEeOoOoOoOoYy............" + // U+0200
+	"................" // U+0240
+
+// latinExtendedFold maps U+1E00..U+1EFF, which includes the Vietnamese
+// letters, in the same way as latinFold
+const latinExtendedFold = "" +
+	"AaBbBbBbCcDdDdDdDdDdEeEeEeEeEeFfGgHhHhHhHhHhIiIiKkKkKkLlLlLlLlMm" + // U+1E00
+	"MmMmNnNnNnNnOoOoOoOoPpPpRrRrRrRrSsSsSsSsSsTtTtTtTtUuUuUuUuUuVvVv" + // U+1E40
+	"WwWwWwWwWwXxXxYyZzZzZzhtwy......AaAaAaAaAaAaAaAaAaAaAaAaEeEeEeEe" + // U+1E80
+	"EeEeEeEeIiIiOoOoOoOoOoOoOoOoOoOoOoOoUuUuUuUuUuUuUuYyYyYyYy......" // U+1EC0
+
+// greekFold maps the accented monotonic Greek letters U+0386..U+03CF to
+// their unaccented lower case base letter
+var greekFold = []rune("" +
+	"α.εηι.ο.υωιαβγδεζηθικλμνξοπρ.στυ" + // U+0386
+	"φχψωιυαεηιυαβγδεζηθικλμνξοπρςστυ" + // U+03A6
+	"φχψωιυουω.") // U+03C6
+
+// transliterationExceptions covers letters whose ASCII form is not a
+// single base letter, along with typographic punctuation and currency
+var transliterationExceptions = map[rune]string{
+	'Æ': "AE", 'æ': "ae", 'Ð': "D", 'ð': "d", 'Ø': "O", 'ø': "o",
+	'Þ': "TH", 'þ': "th", 'ß': "ss", 'ẞ': "SS", 'Đ': "D", 'đ': "d",
+	'Ħ': "H", 'ħ': "h", 'ı': "i", 'Ĳ': "IJ", 'ĳ': "ij", 'ĸ': "k",
+	'Ŀ': "L", 'ŀ': "l", 'Ł': "L", 'ł': "l", 'ŉ': "n", 'Ŋ': "NG",
+	'ŋ': "ng", 'Œ': "OE", 'œ': "oe", 'Ŧ': "T", 'ŧ': "t", 'ſ': "s",
+	'Ə': "E", 'ə': "e", 'ƒ': "f",
+	'×': "x", '‘': "'", '’': "'", '‚': "'", '“': "\"", '”': "\"",
+	'„': "\"", '«': "\"", '»': "\"", '–': "-", '—': "-", '…': "...",
+	'€': "EUR", '£': "GBP", '¥': "JPY", '©': "(c)", '®': "(r)", '™': "TM",
+	' ': " ",
+}
+
+// cyrillic transliterates the Russian, Ukrainian, Belarusian and Serbian
+// alphabets, in lower case
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g", 'ў': "u",
+	'ђ': "dj", 'ј': "j", 'љ': "lj", 'њ': "nj", 'ћ': "c", 'џ': "dz",
+}
+
+// greek transliterates the Greek alphabet, in lower case
+var greek = map[rune]string{
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i",
+	'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x",
+	'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y",
+	'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+}
+
+// Transliterate converts s to ASCII. Latin letters lose their diacritics,
+// Cyrillic and Greek are romanized, typographic punctuation becomes its
+// plain equivalent, and anything without an ASCII form is dropped.
+func Transliterate(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		b.WriteString(transliterateRune(r))
+	}
+	return b.String()
+}
+
+func transliterateRune(r rune) string {
+	if r < utf8.RuneSelf {
+		return string(r)
+	}
+	if t, ok := transliterationExceptions[r]; ok {
+		return t
+	}
+	if r >= 0x00C0 && r < 0x00C0+rune(len(latinFold)) {
+		if c := latinFold[r-0x00C0]; c != '.' {
+			return string(c)
+		}
+	}
+	if r >= 0x1E00 && r < 0x1E00+rune(len(latinExtendedFold)) {
+		if c := latinExtendedFold[r-0x1E00]; c != '.' {
+			return string(c)
+		}
+	}
+
+	lower := unicode.ToLower(r)
+	t, ok := cyrillic[lower]
+	if !ok {
+		if lower >= 0x0386 && lower < 0x0386+rune(len(greekFold)) && greekFold[lower-0x0386] != '.' {
+			lower = greekFold[lower-0x0386]
+		}
+		t, ok = greek[lower]
+	}
+	if ok {
+		if unicode.IsUpper(r) {
+			return upperFirst(t)
+		}
+		return t
+	}
+
+	if unicode.IsSpace(r) {
+		return " "
+	}
+	return ""
+}
+
+// slugSymbols are spelled out by Slugify instead of being dropped
+var slugSymbols = map[rune]string{
+	'&': "and", '@': "at", '%': "percent", '+': "plus", '=': "equals",
+	'€': "euro", '$': "dollar", '£': "pound", '¥': "yen", '♥': "love",
+}
+
+// SlugOptions configures SlugifyWith
+type SlugOptions struct {
+	// Separator joins the words of the slug. It defaults to "-".
+	Separator string
+	// MaxLength limits the slug length in bytes. The slug is cut at a word
+	// boundary unless its first word alone is too long. Zero means no limit.
+	MaxLength int
+	// StopWords are left out of the slug, unless that would leave it empty
+	StopWords []string
+}
+
+// Slugify turns arbitrary text into a lower case, URL-safe slug such as
+// "creme-brulee-and-cafe" for "Crème Brûlée & Café"
+func Slugify(s string) string {
+	return SlugifyWith(s, SlugOptions{})
+}
+
+// SlugifyWith is like Slugify with a custom separator, length limit and
+// stop-word list
+func SlugifyWith(s string, opts SlugOptions) string {
+	sep := opts.Separator
+	if sep == "" {
+		sep = "-"
+	}
+
+	var b strings.Builder
+	for _, r := range s {
+		if word, ok := slugSymbols[r]; ok {
+			b.WriteString(" " + word + " ")
+			continue
+		}
+		b.WriteString(transliterateRune(r))
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(b.String()), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+
+	if len(opts.StopWords) > 0 {
+		stop := make(map[string]bool, len(opts.StopWords))
+		for _, w := range opts.StopWords {
+			stop[strings.ToLower(w)] = true
+		}
+		kept := words[:0:0]
+		for _, w := range words {
+			if !stop[w] {
+				kept = append(kept, w)
+			}
+		}
+		if len(kept) > 0 {
+			words = kept
+		}
+	}
+
+	slug := strings.Join(words, sep)
+	if opts.MaxLength <= 0 || len(slug) <= opts.MaxLength {
+		return slug
+	}
+
+	slug = ""
+	for _, w := range words {
+		next := w
+		if slug != "" {
+			next = slug + sep + w
+		}
+		if len(next) > opts.MaxLength {
+			break
+		}
+		slug = next
+	}
+	if slug == "" && len(words) > 0 {
+		slug = words[0][:opts.MaxLength]
+	}
+	return slug
+}
@@ -0,0 +1,76 @@
+package strings
+
+import "testing"
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Crème Brûlée", "Creme Brulee"},
+		{"Straße", "Strasse"},
+		{"Łódź", "Lodz"},
+		{"Ærøskøbing", "AEroskobing"},
+		{"Tiếng Việt", "Tieng Viet"},
+		{"Москва", "Moskva"},
+		{"Щука и Жук", "Shchuka i Zhuk"},
+		{"Україна", "Ukrayina"},
+		{"Αθήνα", "Athina"},
+		{"“quoted” – dash…", "\"quoted\" - dash..."},
+		{"é", "e"},
+		{"日本", ""},
+		{"plain ascii", "plain ascii"},
+	}
+
+	for _, test := range tests {
+		result := Transliterate(test.input)
+		if result != test.expected {
+			t.Errorf("Transliterate(%q) = %q; expected %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Hello World", "hello-world"},
+		{"Crème Brûlée & Café", "creme-brulee-and-cafe"},
+		{"  --Multiple   spaces__and_punctuation!!  ", "multiple-spaces-and-punctuation"},
+		{"Привет, мир", "privet-mir"},
+		{"Ελληνικά Νέα", "ellinika-nea"},
+		{"100% Pure + Simple", "100-percent-pure-plus-simple"},
+		{"", ""},
+		{"日本語", ""},
+	}
+
+	for _, test := range tests {
+		result := Slugify(test.input)
+		if result != test.expected {
+			t.Errorf("Slugify(%q) = %q; expected %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestSlugifyWith(t *testing.T) {
+	tests := []struct {
+		input    string
+		opts     SlugOptions
+		expected string
+	}{
+		{"Hello World", SlugOptions{Separator: "_"}, "hello_world"},
+		{"The Quick Brown Fox", SlugOptions{MaxLength: 15}, "the-quick-brown"},
+		{"The Quick Brown Fox", SlugOptions{MaxLength: 14}, "the-quick"},
+		{"Supercalifragilistic", SlugOptions{MaxLength: 5}, "super"},
+		{"The Lord of the Rings", SlugOptions{StopWords: []string{"the", "of"}}, "lord-rings"},
+		{"The The", SlugOptions{StopWords: []string{"the"}}, "the-the"},
+	}
+
+	for _, test := range tests {
+		result := SlugifyWith(test.input, test.opts)
+		if result != test.expected {
+			t.Errorf("SlugifyWith(%q, %+v) = %q; expected %q", test.input, test.opts, result, test.expected)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package strings
+
+import (
+	"bufio"
+	"container/heap"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultMaxDistinctWords is the size NewTextStats gives the word
+// frequency table
+const DefaultMaxDistinctWords = 100000
+
+// TextStats accumulates word, line, character and sentence statistics from
+// text streamed through ReadFrom, without holding the text in memory.
+// Only the word frequency table grows with the input, up to
+// MaxDistinctWords entries.
+type TextStats struct {
+	Bytes     int64
+	Runes     int64
+	Words     int64
+	Lines     int64
+	Sentences int64
+	Syllables int64
+
+	// MaxDistinctWords bounds the word frequency table. Once it is full, a
+	// new word replaces the least frequent one and takes over its count
+	// plus one (the Space-Saving algorithm), so the most frequent words
+	// are kept but later counts may be overestimated. Zero means no limit
+	// and a negative value turns the table off.
+	MaxDistinctWords int
+
+	letters   int64
+	freq      wordTable
+	stopWords map[string]bool
+
+	word          []rune
+	sentenceWords bool
+	terminator    bool
+	// decimalPoint is set for a '.' after a digit, which continues the
+	// word as in "3.50" if another digit follows
+	decimalPoint bool
+}
+
+// WordCount is an entry of the TextStats word frequency table
+type WordCount struct {
+	Word  string
+	Count int64
+}
+
+// NewTextStats returns an empty TextStats. Stop words are still counted as
+// words but are left out of the frequency table.
+func NewTextStats(stopWords ...string) *TextStats {
+	s := &TextStats{
+		MaxDistinctWords: DefaultMaxDistinctWords,
+		stopWords:        make(map[string]bool, len(stopWords)),
+	}
+	for _, w := range stopWords {
+		s.stopWords[strings.ToLower(w)] = true
+	}
+	return s
+}
+
+// AnalyzeText reads r to the end and returns its statistics
+func AnalyzeText(r io.Reader, stopWords ...string) (*TextStats, error) {
+	s := NewTextStats(stopWords...)
+	if _, err := s.ReadFrom(r); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ReadFrom adds the text read from r to the statistics until EOF. The end
+// of the input ends the current word, line and sentence, so consecutive
+// calls count as separate documents.
+func (s *TextStats) ReadFrom(r io.Reader) (int64, error) {
+	br := bufio.NewReader(r)
+	var n int64
+	var last rune
+	for {
+		c, size, err := br.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return n, err
+		}
+		n += int64(size)
+		s.Runes++
+		s.add(c)
+		last = c
+	}
+
+	s.Bytes += n
+	if s.decimalPoint {
+		s.decimalPoint, s.terminator = false, true
+	}
+	s.endWord()
+	if s.terminator || s.sentenceWords {
+		s.Sentences++
+	}
+	s.terminator, s.sentenceWords = false, false
+	if n > 0 && last != '\n' {
+		s.Lines++
+	}
+	return n, nil
+}
+
+// add feeds one rune through the word and sentence state machine
+func (s *TextStats) add(c rune) {
+	if c == '\n' {
+		s.Lines++
+	}
+
+	if s.decimalPoint {
+		s.decimalPoint = false
+		if unicode.IsDigit(c) {
+			s.word = append(s.word, '.', c)
+			return
+		}
+		// The point ended the word after all
+		s.endWord()
+		s.terminator = true
+	}
+
+	if s.terminator {
+		// A terminator followed by more text, as in "e.g" or "v2.x",
+		// does not end the sentence
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			s.terminator = false
+		} else if unicode.IsSpace(c) {
+			s.Sentences++
+			s.terminator, s.sentenceWords = false, false
+		}
+	}
+
+	switch {
+	case unicode.IsLetter(c), unicode.IsDigit(c), unicode.IsMark(c):
+		s.word = append(s.word, c)
+	case (c == '\'' || c == '’') && len(s.word) > 0:
+		s.word = append(s.word, c)
+	case c == '.' && len(s.word) > 0 && unicode.IsDigit(s.word[len(s.word)-1]):
+		s.decimalPoint = true
+	default:
+		s.endWord()
+		if (c == '.' || c == '!' || c == '?') && s.sentenceWords {
+			s.terminator = true
+		}
+	}
+}
+
+// endWord records the word being accumulated, if any
+func (s *TextStats) endWord() {
+	word := strings.ToLower(strings.TrimRight(string(s.word), "'’"))
+	s.word = s.word[:0]
+	if word == "" {
+		return
+	}
+
+	s.Words++
+	s.sentenceWords = true
+	for _, r := range word {
+		if r != '\'' && r != '’' && r != '.' {
+			s.letters++
+		}
+	}
+	s.Syllables += int64(countSyllables(word))
+	if !s.stopWords[word] && s.MaxDistinctWords >= 0 {
+		s.freq.add(word, s.MaxDistinctWords)
+	}
+}
+
+// wordTable counts words in a min-heap ordered by count, with an index
+// from word to heap position, so that the least frequent word is found
+// in constant time when the table is full
+type wordTable struct {
+	heap  []WordCount
+	index map[string]int
+}
+
+// add counts word, evicting the least frequent word if the table already
+// holds max words and max is positive
+func (t *wordTable) add(word string, max int) {
+	if i, ok := t.index[word]; ok {
+		t.heap[i].Count++
+		heap.Fix(t, i)
+		return
+	}
+	if t.index == nil {
+		t.index = make(map[string]int)
+	}
+	if max <= 0 || len(t.heap) < max {
+		heap.Push(t, WordCount{Word: word, Count: 1})
+		return
+	}
+	evicted := t.heap[0]
+	delete(t.index, evicted.Word)
+	t.heap[0] = WordCount{Word: word, Count: evicted.Count + 1}
+	t.index[word] = 0
+	heap.Fix(t, 0)
+}
+
+func (t *wordTable) Len() int           { return len(t.heap) }
+func (t *wordTable) Less(i, j int) bool { return t.heap[i].Count < t.heap[j].Count }
+
+func (t *wordTable) Swap(i, j int) {
+	t.heap[i], t.heap[j] = t.heap[j], t.heap[i]
+	t.index[t.heap[i].Word] = i
+	t.index[t.heap[j].Word] = j
+}
+
+func (t *wordTable) Push(x interface{}) {
+	wc := x.(WordCount)
+	t.index[wc.Word] = len(t.heap)
+	t.heap = append(t.heap, wc)
+}
+
+func (t *wordTable) Pop() interface{} {
+	wc := t.heap[len(t.heap)-1]
+	t.heap = t.heap[:len(t.heap)-1]
+	delete(t.index, wc.Word)
+	return wc
+}
+
+// AverageWordLength returns the mean number of characters per word
+func (s *TextStats) AverageWordLength() float64 {
+	if s.Words == 0 {
+		return 0
+	}
+	return float64(s.letters) / float64(s.Words)
+}
+
+// TopWords returns the n most frequent words, most frequent first, with
+// ties in alphabetical order. A non-positive n returns every word.
+func (s *TextStats) TopWords(n int) []WordCount {
+	counts := append([]WordCount(nil), s.freq.heap...)
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Word < counts[j].Word
+	})
+	if n > 0 && n < len(counts) {
+		counts = counts[:n]
+	}
+	return counts
+}
+
+// FleschReadingEase returns the Flesch reading-ease score. Higher is easier:
+// 90-100 reads at fifth grade level, 0-30 needs a university graduate.
+func (s *TextStats) FleschReadingEase() float64 {
+	wordsPerSentence, syllablesPerWord, ok := s.readabilityRatios()
+	if !ok {
+		return 0
+	}
+	return 206.835 - 1.015*wordsPerSentence - 84.6*syllablesPerWord
+}
+
+// FleschKincaidGrade returns the Flesch-Kincaid grade level, the US school
+// grade needed to understand the text
+func (s *TextStats) FleschKincaidGrade() float64 {
+	wordsPerSentence, syllablesPerWord, ok := s.readabilityRatios()
+	if !ok {
+		return 0
+	}
+	return 0.39*wordsPerSentence + 11.8*syllablesPerWord - 15.59
+}
+
+func (s *TextStats) readabilityRatios() (float64, float64, bool) {
+	if s.Words == 0 {
+		return 0, 0, false
+	}
+	sentences := s.Sentences
+	if sentences == 0 {
+		sentences = 1
+	}
+	return float64(s.Words) / float64(sentences), float64(s.Syllables) / float64(s.Words), true
+}
+
+// countSyllables estimates the syllables of a lower case English word by
+// counting vowel groups, ignoring a silent final "e"
+func countSyllables(word string) int {
+	count := 0
+	inVowel := false
+	runes := []rune(word)
+	for _, r := range runes {
+		vowel := strings.ContainsRune("aeiouy", r)
+		if vowel && !inVowel {
+			count++
+		}
+		inVowel = vowel
+	}
+
+	n := len(runes)
+	if n > 2 && runes[n-1] == 'e' && runes[n-2] != 'l' && !strings.ContainsRune("aeiouy", runes[n-2]) && count > 1 {
+		count--
+	}
+	if count == 0 {
+		count = 1
+	}
+	return count
+}
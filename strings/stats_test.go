@@ -0,0 +1,190 @@
+package strings
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeText(t *testing.T) {
+	text := "The cat sat on the mat. The dog didn't!\nIt cost 3.50 dollars?\n"
+	stats, err := AnalyzeText(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("AnalyzeText returned error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		result   int64
+		expected int64
+	}{
+		{"Bytes", stats.Bytes, int64(len(text))},
+		{"Runes", stats.Runes, int64(len([]rune(text)))},
+		{"Words", stats.Words, int64(CountWords(text))},
+		{"Lines", stats.Lines, 2},
+		{"Sentences", stats.Sentences, 3},
+	}
+
+	for _, test := range tests {
+		if test.result != test.expected {
+			t.Errorf("%s = %d; expected %d", test.name, test.result, test.expected)
+		}
+	}
+
+	top := stats.TopWords(2)
+	expected := []WordCount{{"the", 3}, {"3.50", 1}}
+	if !reflect.DeepEqual(top, expected) {
+		t.Errorf("TopWords(2) = %v; expected %v", top, expected)
+	}
+}
+
+func TestTextStatsLines(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"", 0},
+		{"one line", 1},
+		{"one line\n", 1},
+		{"two\nlines", 2},
+		{"\n\n", 2},
+	}
+
+	for _, test := range tests {
+		stats, _ := AnalyzeText(strings.NewReader(test.input))
+		if stats.Lines != test.expected {
+			t.Errorf("Lines(%q) = %d; expected %d", test.input, stats.Lines, test.expected)
+		}
+	}
+}
+
+func TestTextStatsDecimals(t *testing.T) {
+	tests := []struct {
+		input     string
+		words     []string
+		sentences int64
+	}{
+		{"Pi is 3.14 today.", []string{"3.14", "is", "pi", "today"}, 1},
+		{"It costs 3. Cheap.", []string{"3", "cheap", "costs", "it"}, 2},
+		{"Version 1.2.3 ships", []string{"1.2.3", "ships", "version"}, 1},
+		{"Count to 10.", []string{"10", "count", "to"}, 1},
+		{"v2.x next", []string{"next", "v2", "x"}, 1},
+	}
+
+	for _, test := range tests {
+		stats, _ := AnalyzeText(strings.NewReader(test.input))
+		var words []string
+		for _, wc := range stats.TopWords(0) {
+			words = append(words, wc.Word)
+		}
+		if !reflect.DeepEqual(words, test.words) || stats.Sentences != test.sentences {
+			t.Errorf("AnalyzeText(%q) words %q, %d sentences; expected %q, %d", test.input, words, stats.Sentences, test.words, test.sentences)
+		}
+	}
+}
+
+func TestTextStatsStopWords(t *testing.T) {
+	stats, _ := AnalyzeText(strings.NewReader("The end of the road and the end"), "the", "of", "and")
+
+	if stats.Words != 8 {
+		t.Errorf("Words = %d; expected 8", stats.Words)
+	}
+	expected := []WordCount{{"end", 2}, {"road", 1}}
+	if top := stats.TopWords(0); !reflect.DeepEqual(top, expected) {
+		t.Errorf("TopWords(0) = %v; expected %v", top, expected)
+	}
+}
+
+func TestTextStatsMaxDistinctWords(t *testing.T) {
+	// Frequent words survive a stream of unique ones
+	var text strings.Builder
+	for i := 0; i < 1000; i++ {
+		text.WriteString("common word" + strconv.Itoa(i) + " ")
+		if i%2 == 0 {
+			text.WriteString("frequent ")
+		}
+	}
+
+	stats := NewTextStats()
+	stats.MaxDistinctWords = 10
+	stats.ReadFrom(strings.NewReader(text.String()))
+	if stats.Words != 2500 {
+		t.Errorf("Words = %d; expected 2500", stats.Words)
+	}
+	if n := len(stats.TopWords(0)); n != 10 {
+		t.Errorf("TopWords(0) has %d words; expected 10", n)
+	}
+	expected := []WordCount{{"common", 1000}, {"frequent", 500}}
+	if top := stats.TopWords(2); !reflect.DeepEqual(top, expected) {
+		t.Errorf("TopWords(2) = %v; expected %v", top, expected)
+	}
+
+	off := NewTextStats()
+	off.MaxDistinctWords = -1
+	off.ReadFrom(strings.NewReader(text.String()))
+	if off.Words != 2500 || len(off.TopWords(0)) != 0 {
+		t.Errorf("with the table off, Words = %d and TopWords(0) = %v", off.Words, off.TopWords(0))
+	}
+
+	var unlimited TextStats
+	unlimited.ReadFrom(strings.NewReader(text.String()))
+	if n := len(unlimited.TopWords(0)); n != 1002 {
+		t.Errorf("zero value TopWords(0) has %d words; expected 1002", n)
+	}
+}
+
+func TestTextStatsReadFromAccumulates(t *testing.T) {
+	stats := NewTextStats()
+	for _, chunk := range []string{"First document.", "Second one here"} {
+		if _, err := stats.ReadFrom(strings.NewReader(chunk)); err != nil {
+			t.Fatalf("ReadFrom returned error: %v", err)
+		}
+	}
+
+	if stats.Words != 5 || stats.Sentences != 2 || stats.Lines != 2 {
+		t.Errorf("Words, Sentences, Lines = %d, %d, %d; expected 5, 2, 2", stats.Words, stats.Sentences, stats.Lines)
+	}
+	if avg := stats.AverageWordLength(); math.Abs(avg-26.0/5.0) > 1e-9 {
+		t.Errorf("AverageWordLength = %v; expected %v", avg, 26.0/5.0)
+	}
+}
+
+func TestReadability(t *testing.T) {
+	easy, _ := AnalyzeText(strings.NewReader("The cat sat. The dog ran. We had fun."))
+	hard, _ := AnalyzeText(strings.NewReader("Institutional considerations necessitate comprehensive organizational restructuring notwithstanding unprecedented administrative complications."))
+
+	if easy.FleschReadingEase() <= hard.FleschReadingEase() {
+		t.Errorf("FleschReadingEase: easy %.1f should exceed hard %.1f", easy.FleschReadingEase(), hard.FleschReadingEase())
+	}
+	if easy.FleschKincaidGrade() >= hard.FleschKincaidGrade() {
+		t.Errorf("FleschKincaidGrade: easy %.1f should be below hard %.1f", easy.FleschKincaidGrade(), hard.FleschKincaidGrade())
+	}
+
+	empty := NewTextStats()
+	if empty.FleschReadingEase() != 0 || empty.AverageWordLength() != 0 {
+		t.Error("empty TextStats should report zero scores")
+	}
+}
+
+func TestCountSyllables(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{"cat", 1},
+		{"table", 2},
+		{"make", 1},
+		{"beautiful", 3},
+		{"the", 1},
+		{"rhythm", 1},
+	}
+
+	for _, test := range tests {
+		result := countSyllables(test.input)
+		if result != test.expected {
+			t.Errorf("countSyllables(%q) = %d; expected %d", test.input, result, test.expected)
+		}
+	}
+}
@@ -2,18 +2,14 @@
 package strings
 
 import (
-	"regexp"
 	"strings"
 	"unicode"
 )
 
-// Reverse returns a string with characters in reverse order
+// Reverse returns a string with its user-perceived characters in reverse
+// order, keeping combining marks, flags and emoji sequences intact
 func Reverse(s string) string {
-	runes := []rune(s)
-	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
-		runes[i], runes[j] = runes[j], runes[i]
-	}
-	return string(runes)
+	return reverseGraphemes(s)
 }
 
 // IsPalindrome checks if a string is a palindrome (ignoring case and spaces)
@@ -22,40 +18,41 @@ func IsPalindrome(s string) bool {
 	return cleaned == Reverse(cleaned)
 }
 
-// Capitalize capitalizes the first letter of each word in a string
+// IsPalindromeWith checks if a string is a palindrome after normalizing it
+// to form and fully case-folding it, ignoring all whitespace, so mixed
+// composed and decomposed accents compare equal
+func IsPalindromeWith(s string, form NormalizationForm) bool {
+	cleaned := caseless(RemoveSpaces(s), form)
+	return cleaned == Normalize(Reverse(cleaned), form)
+}
+
+// Capitalize capitalizes the first letter of each word in a string.
+// Use a TitleCaser for acronyms, name prefixes and language specific rules.
 func Capitalize(s string) string {
 	return strings.Title(strings.ToLower(s))
 }
 
-// CamelCase converts a string to camelCase
-func CamelCase(s string) string {
-	words := strings.Fields(s)
-	if len(words) == 0 {
-		return s
+// Truncate truncates a string to a specified number of user-perceived
+// characters, including the optional suffix. Grapheme clusters are never
+// split; if the suffix alone is longer than length it is truncated itself.
+func Truncate(s string, length int, suffix string) string {
+	if length < 0 {
+		length = 0
 	}
-	
-	result := strings.ToLower(words[0])
-	for _, word := range words[1:] {
-		if len(word) > 0 {
-			result += strings.ToUpper(string(word[0])) + strings.ToLower(word[1:])
-		}
+	if GraphemeLength(s) <= length {
+		return s
 	}
-	return result
-}
 
-// SnakeCase converts a string to snake_case
-func SnakeCase(s string) string {
-	re := regexp.MustCompile(`([a-z0-9])([A-Z])`)
-	snake := re.ReplaceAllString(s, `${1}_${2}`)
-	return strings.ToLower(strings.ReplaceAll(snake, " ", "_"))
-}
+	keep := length - GraphemeLength(suffix)
+	if keep < 0 {
+		return Truncate(suffix, length, "")
+	}
 
-// Truncate truncates a string to a specified length with optional suffix
-func Truncate(s string, length int, suffix string) string {
-	if len(s) <= length {
-		return s
+	end := 0
+	for i := 0; i < keep; i++ {
+		end += firstGrapheme(s[end:])
 	}
-	return s[:length-len(suffix)] + suffix
+	return s[:end] + suffix
 }
 
 // RemoveSpaces removes all whitespace characters from a string
@@ -78,7 +75,14 @@ func IsEmpty(s string) bool {
 	return strings.TrimSpace(s) == ""
 }
 
-// Contains checks if a string contains a substring (case-insensitive)
+// ContainsIgnoreCase checks if a string contains a substring (case-insensitive)
 func ContainsIgnoreCase(s, substr string) bool {
 	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
 }
+
+// ContainsIgnoreCaseWith checks if a string contains a substring after
+// normalizing both to form and fully case-folding them, so "STRASSE"
+// contains "straße"
+func ContainsIgnoreCaseWith(s, substr string, form NormalizationForm) bool {
+	return strings.Contains(caseless(s, form), caseless(substr, form))
+}
@@ -50,6 +50,9 @@ func TestCapitalize(t *testing.T) {
 		{"HELLO WORLD", "Hello World"},
 		{"", ""},
 		{"a", "A"},
+		// No acronym or name prefix rules, unlike TitleCaser
+		{"NASA launch", "Nasa Launch"},
+		{"mcdonald", "Mcdonald"},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,182 @@
+package strings
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Language selects the casing and small-word rules used by a TitleCaser
+type Language string
+
+// Languages with dedicated title casing rules
+const (
+	LanguageNone    Language = ""
+	LanguageEnglish Language = "en"
+	LanguageTurkish Language = "tr"
+	LanguageAzeri   Language = "az"
+)
+
+// englishSmallWords are the articles, conjunctions and short prepositions
+// that English title case leaves in lower case
+var englishSmallWords = []string{
+	"a", "an", "and", "as", "at", "but", "by", "en", "for", "if", "in",
+	"nor", "of", "on", "or", "per", "the", "to", "v", "vs", "via",
+}
+
+// TitleCaser converts text to title case according to language rules.
+//
+// Every whitespace-separated word is capitalized, and so is every part of a
+// hyphenated word ("X-Ray"). Name prefixes are handled: "o'neil" becomes
+// "O'Neil" and "mcdonald" becomes "McDonald", while contractions such as
+// "don't" are left alone. SmallWords stay in lower case unless they start
+// or end the text or follow a colon, and Acronyms are written exactly as
+// listed whatever their input case.
+type TitleCaser struct {
+	Language   Language
+	SmallWords []string
+	Acronyms   []string
+}
+
+// NewTitleCaser returns a TitleCaser with the default rules for a language
+func NewTitleCaser(lang Language) *TitleCaser {
+	c := &TitleCaser{Language: lang}
+	if lang == LanguageEnglish {
+		c.SmallWords = append([]string(nil), englishSmallWords...)
+	}
+	return c
+}
+
+// special returns the language specific case mapping, if any
+func (c *TitleCaser) special() unicode.SpecialCase {
+	switch c.Language {
+	case LanguageTurkish, LanguageAzeri:
+		return unicode.TurkishCase
+	}
+	return nil
+}
+
+func (c *TitleCaser) lower(s string) string {
+	if sc := c.special(); sc != nil {
+		return strings.ToLowerSpecial(sc, s)
+	}
+	return strings.ToLower(s)
+}
+
+func (c *TitleCaser) titleRune(r rune) rune {
+	if sc := c.special(); sc != nil {
+		return sc.ToTitle(r)
+	}
+	return unicode.ToTitle(r)
+}
+
+// Title converts s to title case, preserving the whitespace between words
+func (c *TitleCaser) Title(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	fields := fieldSpans(s)
+	prev := 0
+	afterColon := false
+	for i, span := range fields {
+		b.WriteString(s[prev:span[0]])
+		prev = span[1]
+
+		word := s[span[0]:span[1]]
+		edge := i == 0 || i == len(fields)-1 || afterColon
+		b.WriteString(c.titleWord(word, edge))
+		afterColon = strings.HasSuffix(word, ":")
+	}
+	b.WriteString(s[prev:])
+
+	return b.String()
+}
+
+// titleWord cases a single whitespace-delimited word. Leading and trailing
+// punctuation such as quotes or brackets is kept as is.
+func (c *TitleCaser) titleWord(word string, edge bool) string {
+	start := strings.IndexFunc(word, isWordRune)
+	if start < 0 {
+		return word
+	}
+	end := strings.LastIndexFunc(word, isWordRune)
+	_, size := utf8.DecodeRuneInString(word[end:])
+	end += size
+	core := word[start:end]
+
+	for _, acronym := range c.Acronyms {
+		if strings.EqualFold(core, acronym) {
+			return word[:start] + acronym + word[end:]
+		}
+	}
+
+	core = c.lower(core)
+	if !edge {
+		for _, small := range c.SmallWords {
+			if core == c.lower(small) {
+				return word[:start] + core + word[end:]
+			}
+		}
+	}
+
+	parts := strings.Split(core, "-")
+	for i, part := range parts {
+		parts[i] = c.titlePart(part)
+	}
+	return word[:start] + strings.Join(parts, "-") + word[end:]
+}
+
+// titlePart capitalizes one hyphen-free part of a word, applying the
+// apostrophe and "Mc" name prefix rules
+func (c *TitleCaser) titlePart(part string) string {
+	part = c.upperFirst(part)
+
+	if i := strings.IndexAny(part, "'’"); i >= 0 {
+		head, tail := part[:i], part[i:]
+		_, size := utf8.DecodeRuneInString(tail)
+		// A single letter before the apostrophe is a name prefix (O'Neil,
+		// D'Arcy); anything longer is a contraction (Don't, Rock'n'roll)
+		if utf8.RuneCountInString(head) == 1 && utf8.RuneCountInString(tail[size:]) > 1 {
+			return head + tail[:size] + c.upperFirst(tail[size:])
+		}
+		return part
+	}
+
+	if strings.HasPrefix(part, "Mc") && utf8.RuneCountInString(part) > 3 {
+		return "Mc" + c.upperFirst(part[2:])
+	}
+	return part
+}
+
+func (c *TitleCaser) upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 {
+		return s
+	}
+	return string(c.titleRune(r)) + s[size:]
+}
+
+// isWordRune reports whether r can be part of a word's core
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// fieldSpans returns the byte offsets of the whitespace-separated fields of s
+func fieldSpans(s string) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range s {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				spans = append(spans, [2]int{start, i})
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(s)})
+	}
+	return spans
+}
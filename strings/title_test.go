@@ -0,0 +1,41 @@
+package strings
+
+import "testing"
+
+func TestTitleCaser(t *testing.T) {
+	english := NewTitleCaser(LanguageEnglish)
+	english.Acronyms = []string{"NASA", "HTTP", "iOS"}
+	turkish := NewTitleCaser(LanguageTurkish)
+	azeri := NewTitleCaser(LanguageAzeri)
+	plain := NewTitleCaser(LanguageNone)
+
+	tests := []struct {
+		caser    *TitleCaser
+		input    string
+		expected string
+	}{
+		{english, "the lord of the rings", "The Lord of the Rings"},
+		{english, "a tale of two cities", "A Tale of Two Cities"},
+		{english, "what are you looking at", "What Are You Looking At"},
+		{english, "star wars: a new hope", "Star Wars: A New Hope"},
+		{english, "nasa and the http protocol", "NASA and the HTTP Protocol"},
+		{english, "IOS apps", "iOS Apps"},
+		{english, "\"the end\" of it", "\"The End\" of It"},
+		{plain, "o'neil and d'arcy", "O'Neil And D'Arcy"},
+		{plain, "don't stop", "Don't Stop"},
+		{plain, "mcdonald farm", "McDonald Farm"},
+		{plain, "x-ray vision", "X-Ray Vision"},
+		{plain, "  spaced   out  ", "  Spaced   Out  "},
+		{turkish, "istanbul ışık", "İstanbul Işık"},
+		{turkish, "İZMİR IĞDIR", "İzmir Iğdır"},
+		{azeri, "iki", "İki"},
+		{plain, "istanbul", "Istanbul"},
+	}
+
+	for _, test := range tests {
+		result := test.caser.Title(test.input)
+		if result != test.expected {
+			t.Errorf("Title(%q) [%q] = %q; expected %q", test.input, test.caser.Language, result, test.expected)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package strings
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WrapOptions configures WrapWith
+type WrapOptions struct {
+	// Width is the maximum display width of a line, indentation included
+	Width int
+	// Indent is written before the first line of every paragraph
+	Indent string
+	// HangingIndent is written before every following line of a paragraph
+	HangingIndent string
+}
+
+// Wrap reflows text so that no line is wider than width terminal columns.
+// Lines break between words; a word wider than a whole line is split
+// between grapheme clusters. Paragraphs separated by blank lines are kept
+// apart, while single line breaks inside a paragraph are reflowed.
+func Wrap(s string, width int) string {
+	return WrapWith(s, WrapOptions{Width: width})
+}
+
+// WrapWith is like Wrap with first-line and hanging indentation
+func WrapWith(s string, opts WrapOptions) string {
+	if opts.Width <= 0 {
+		return s
+	}
+
+	var out []string
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			out = append(out, wrapParagraph(strings.Fields(strings.Join(paragraph, " ")), opts)...)
+			paragraph = nil
+		}
+	}
+
+	for _, line := range strings.Split(s, "\n") {
+		if IsEmpty(line) {
+			flush()
+			out = append(out, "")
+			continue
+		}
+		paragraph = append(paragraph, line)
+	}
+	flush()
+
+	return strings.Join(out, "\n")
+}
+
+// wrapParagraph lays out the words of a single paragraph
+func wrapParagraph(words []string, opts WrapOptions) []string {
+	var lines []string
+	var line strings.Builder
+	lineWidth := 0
+	prefix := opts.Indent
+
+	avail := func() int {
+		if n := opts.Width - DisplayWidth(prefix); n > 0 {
+			return n
+		}
+		return 1
+	}
+	flush := func() {
+		lines = append(lines, prefix+line.String())
+		line.Reset()
+		lineWidth = 0
+		prefix = opts.HangingIndent
+	}
+
+	for _, word := range words {
+		w := DisplayWidth(word)
+		switch {
+		case lineWidth > 0 && lineWidth+1+w <= avail():
+			line.WriteByte(' ')
+			line.WriteString(word)
+			lineWidth += 1 + w
+			continue
+		case lineWidth > 0:
+			flush()
+		}
+
+		// The word starts a new line, hard-breaking it if it cannot fit
+		for w > avail() {
+			head, rest := splitWidth(word, avail())
+			line.WriteString(head)
+			flush()
+			word, w = rest, DisplayWidth(rest)
+		}
+		line.WriteString(word)
+		lineWidth = w
+	}
+	if lineWidth > 0 {
+		flush()
+	}
+
+	return lines
+}
+
+// splitWidth splits s after as many grapheme clusters as fit in width
+// columns, always taking at least one
+func splitWidth(s string, width int) (string, string) {
+	used, end := 0, 0
+	for end < len(s) {
+		n := firstGrapheme(s[end:])
+		w := graphemeWidth(s[end : end+n])
+		if end > 0 && used+w > width {
+			break
+		}
+		used += w
+		end += n
+	}
+	return s[:end], s[end:]
+}
+
+// Indent adds prefix to the start of every non-blank line of s
+func Indent(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if !IsEmpty(line) {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Dedent removes the longest whitespace prefix shared by every non-blank
+// line of s. Lines consisting only of whitespace become empty.
+func Dedent(s string) string {
+	lines := strings.Split(s, "\n")
+
+	common := ""
+	first := true
+	for _, line := range lines {
+		if IsEmpty(line) {
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeftFunc(line, unicode.IsSpace))]
+		if first {
+			common, first = indent, false
+			continue
+		}
+		// Shorten a whole rune at a time, so that different multibyte
+		// spaces sharing leading bytes are not cut apart
+		for !strings.HasPrefix(indent, common) {
+			_, size := utf8.DecodeLastRuneInString(common)
+			common = common[:len(common)-size]
+		}
+	}
+
+	for i, line := range lines {
+		if IsEmpty(line) {
+			lines[i] = ""
+		} else {
+			lines[i] = line[len(common):]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PadLeft right-aligns s in a field of width columns by prepending pad
+func PadLeft(s string, width int, pad rune) string {
+	return padding(width-DisplayWidth(s), pad) + s
+}
+
+// PadRight left-aligns s in a field of width columns by appending pad
+func PadRight(s string, width int, pad rune) string {
+	return s + padding(width-DisplayWidth(s), pad)
+}
+
+// Center centers s in a field of width columns, putting the extra pad on
+// the right when the padding cannot be split evenly
+func Center(s string, width int, pad rune) string {
+	gap := width - DisplayWidth(s)
+	if gap <= 0 {
+		return s
+	}
+	return padding(gap/2, pad) + s + padding(gap-gap/2, pad)
+}
+
+// padding returns pad repeated to fill columns, topped up with spaces when
+// a wide pad rune does not divide the gap evenly
+func padding(columns int, pad rune) string {
+	if columns <= 0 {
+		return ""
+	}
+	w := runeWidth(pad)
+	if w <= 0 {
+		return strings.Repeat(" ", columns)
+	}
+	return strings.Repeat(string(pad), columns/w) + strings.Repeat(" ", columns%w)
+}
@@ -0,0 +1,95 @@
+package strings
+
+import "testing"
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		input    string
+		width    int
+		expected string
+	}{
+		{"the quick brown fox jumps over the lazy dog", 10, "the quick\nbrown fox\njumps over\nthe lazy\ndog"},
+		{"short", 10, "short"},
+		{"a verylongwordthatcannotfit here", 8, "a\nverylong\nwordthat\ncannotfi\nt here"},
+		{"first paragraph\nstill first\n\nsecond", 20, "first paragraph\nstill first\n\nsecond"},
+		{"one two\nthree", 20, "one two three"},
+		{"日本語のテキスト です", 6, "日本語\nのテキ\nスト\nです"},
+		{"trailing newline\n", 40, "trailing newline\n"},
+		{"unchanged", 0, "unchanged"},
+	}
+
+	for _, test := range tests {
+		result := Wrap(test.input, test.width)
+		if result != test.expected {
+			t.Errorf("Wrap(%q, %d) = %q; expected %q", test.input, test.width, result, test.expected)
+		}
+	}
+}
+
+func TestWrapWith(t *testing.T) {
+	opts := WrapOptions{Width: 16, Indent: "  - ", HangingIndent: "    "}
+	input := "install the package and its dependencies"
+	expected := "  - install the\n    package and\n    its\n    dependencies"
+
+	result := WrapWith(input, opts)
+	if result != expected {
+		t.Errorf("WrapWith(%q) = %q; expected %q", input, result, expected)
+	}
+}
+
+func TestIndent(t *testing.T) {
+	input := "line one\n\nline two"
+	expected := "> line one\n\n> line two"
+
+	result := Indent(input, "> ")
+	if result != expected {
+		t.Errorf("Indent(%q) = %q; expected %q", input, result, expected)
+	}
+}
+
+func TestDedent(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"    a\n      b\n    c", "a\n  b\nc"},
+		{"\tx\n\t\ty", "x\n\ty"},
+		{"  a\n   \n  b", "a\n\nb"},
+		{"a\n  b", "a\n  b"},
+		// U+2000 and U+2003 share their first two bytes
+		{"\u2000\u2000a\n\u2000\u2003b", "\u2000a\n\u2003b"},
+		{"\u2003a\n\u2000b", "\u2003a\n\u2000b"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		result := Dedent(test.input)
+		if result != test.expected {
+			t.Errorf("Dedent(%q) = %q; expected %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestPadding(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   string
+		expected string
+	}{
+		{"PadLeft", PadLeft("42", 5, ' '), "   42"},
+		{"PadLeft", PadLeft("42", 5, '0'), "00042"},
+		{"PadLeft", PadLeft("toolong", 3, ' '), "toolong"},
+		{"PadRight", PadRight("ab", 4, '.'), "ab.."},
+		{"PadRight", PadRight("日本", 6, ' '), "日本  "},
+		{"Center", Center("hi", 6, '*'), "**hi**"},
+		{"Center", Center("hi", 7, ' '), "  hi   "},
+		{"Center", Center("日本", 8, '-'), "--日本--"},
+		{"PadRight", PadRight("a", 4, '＊'), "a＊ "},
+	}
+
+	for _, test := range tests {
+		if test.result != test.expected {
+			t.Errorf("%s = %q; expected %q", test.name, test.result, test.expected)
+		}
+	}
+}
@@ -4,6 +4,8 @@ package time
 import (
         "fmt"
         "time"
+
+        ustrings "github.com/yourusername/goutils/strings"
 )
 
 // FormatDuration formats a duration into a human-readable string
@@ -63,40 +65,25 @@ func TimeAgo(t time.Time) string {
                 return "just now"
         }
         if diff < time.Hour {
-                minutes := int(diff.Minutes())
-                if minutes == 1 {
-                        return "1 minute ago"
-                }
-                return fmt.Sprintf("%d minutes ago", minutes)
+                return ago(int(diff.Minutes()), "minute")
         }
         if diff < 24*time.Hour {
-                hours := int(diff.Hours())
-                if hours == 1 {
-                        return "1 hour ago"
-                }
-                return fmt.Sprintf("%d hours ago", hours)
+                return ago(int(diff.Hours()), "hour")
         }
         
         days := int(diff.Hours() / 24)
-        if days == 1 {
-                return "1 day ago"
-        }
         if days < 30 {
-                return fmt.Sprintf("%d days ago", days)
+                return ago(days, "day")
         }
         if days < 365 {
-                months := days / 30
-                if months == 1 {
-                        return "1 month ago"
-                }
-                return fmt.Sprintf("%d months ago", months)
-        }
-        
-        years := days / 365
-        if years == 1 {
-                return "1 year ago"
+                return ago(days/30, "month")
         }
-        return fmt.Sprintf("%d years ago", years)
+        return ago(days/365, "year")
+}
+
+// ago formats a count of units in the past, such as "3 days ago"
+func ago(count int, unit string) string {
+        return ustrings.PluralizeCount(count, unit) + " ago"
 }
 
 // StartOfDay returns the start of the day for a given time
@@ -130,3 +117,15 @@ func IsLeapYear(year int) bool {
 func DaysInMonth(year int, month time.Month) int {
         return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
 }
+
+// IsExpired checks if expiry has passed at now, tolerating clock skew of up
+// to leeway between the machine that set expiry and this one
+func IsExpired(expiry, now time.Time, leeway time.Duration) bool {
+        return now.After(expiry.Add(leeway))
+}
+
+// IsNotYetValid checks if start is still in the future at now, tolerating
+// clock skew of up to leeway
+func IsNotYetValid(start, now time.Time, leeway time.Duration) bool {
+        return now.Add(leeway).Before(start)
+}
@@ -188,3 +188,29 @@ func TestDaysInMonth(t *testing.T) {
                 }
         }
 }
+
+func TestIsExpiredAndIsNotYetValid(t *testing.T) {
+        now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+        tests := []struct {
+                at       time.Time
+                leeway   time.Duration
+                expired  bool
+                notValid bool
+        }{
+                {now.Add(-time.Minute), 0, true, false},
+                {now.Add(-time.Minute), 2 * time.Minute, false, false},
+                {now.Add(time.Minute), 0, false, true},
+                {now.Add(time.Minute), 2 * time.Minute, false, false},
+                {now, 0, false, false},
+        }
+
+        for _, test := range tests {
+                if result := IsExpired(test.at, now, test.leeway); result != test.expired {
+                        t.Errorf("IsExpired(%v, %v) = %v; expected %v", test.at, test.leeway, result, test.expired)
+                }
+                if result := IsNotYetValid(test.at, now, test.leeway); result != test.notValid {
+                        t.Errorf("IsNotYetValid(%v, %v) = %v; expected %v", test.at, test.leeway, result, test.notValid)
+                }
+        }
+}